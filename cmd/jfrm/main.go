@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
 
 	"github.com/bhanurp/jfrm/internal/cli/commands"
+	"github.com/bhanurp/jfrm/internal/config"
+	"github.com/bhanurp/jfrm/internal/resolver"
 	"github.com/urfave/cli/v2"
 )
 
@@ -13,20 +17,75 @@ func main() {
 		Name:  "jfrm",
 		Usage: "Manage releases and dependencies for JFrog projects",
 		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "config",
+				Usage: "Path to the jfrm configuration file",
+				Value: config.DefaultFile,
+			},
 			&cli.BoolFlag{
 				Name:    "dry-run",
 				Aliases: []string{"d"},
 				Usage:   "Run in dry-run mode (no changes will be made)",
 			},
+			&cli.IntFlag{
+				Name:  "parallelism",
+				Usage: "Maximum number of module versions resolved concurrently",
+				Value: resolver.DefaultParallelism,
+			},
+			&cli.DurationFlag{
+				Name:  "request-timeout",
+				Usage: "Timeout for resolving a single module version",
+				Value: resolver.DefaultTimeout,
+			},
+			&cli.BoolFlag{
+				Name:  "no-cache",
+				Usage: "Bypass the on-disk HTTP cache",
+			},
+			&cli.StringFlag{
+				Name:  "forge",
+				Usage: "Code hosting service: github, gitlab or gitea (default: detected from the git remote)",
+			},
+			&cli.StringFlag{
+				Name:    "github-host",
+				Usage:   "GitHub host, e.g. a GitHub Enterprise Server (default: the host of the git remote)",
+				EnvVars: []string{"GH_HOST"},
+			},
+			&cli.StringFlag{
+				Name:  "github-api-url",
+				Usage: "GitHub REST API root (default: https://api.github.com, or https://<host>/api/v3 for Enterprise Server)",
+			},
+			&cli.BoolFlag{
+				Name:    "debug",
+				Usage:   "Log every HTTP request with its status, duration and retries",
+				EnvVars: []string{"JFRM_DEBUG"},
+			},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "Never contact the network; use the HTTP cache and $GOMODCACHE, marking results as possibly stale",
+			},
 		},
+		Before: commands.Setup,
 		Commands: []*cli.Command{
 			commands.UpdateDependencies(),
 			commands.CheckDependencies(),
 			commands.GenerateReport(),
+			commands.Report(),
+			commands.NextVersion(),
+			commands.Release(),
+			commands.ReleaseNotes(),
+			commands.ReleaseChain(),
+			commands.Impact(),
+			commands.Plan(),
+			commands.Apply(),
+			commands.PR(),
+			commands.Verify(),
+			commands.Cache(),
 		},
 	}
 
-	if err := app.Run(os.Args); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := app.RunContext(ctx, os.Args); err != nil {
 		log.Fatalf("Error: %v", err)
 	}
 }
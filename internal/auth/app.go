@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/httpclient"
+)
+
+// DefaultAPIBase is the GitHub REST API root
+const DefaultAPIBase = "https://api.github.com"
+
+// App mints installation access tokens for a GitHub App
+type App struct {
+	// ID is the numeric app ID
+	ID int64
+	// PrivateKey is the PEM-encoded private key of the app
+	PrivateKey []byte
+	// InstallationID selects the installation; when zero it is looked up from Repo
+	InstallationID int64
+	// Repo is the owner/name slug used to find the installation
+	Repo string
+	// Host is the GitHub host the app is installed on (default DefaultHost)
+	Host string
+	// APIBase is the REST API root of Host (default DefaultAPIBase)
+	APIBase string
+
+	now func() time.Time
+}
+
+// LoadPrivateKey reads a PEM private key from path
+func LoadPrivateKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub App private key: %w", err)
+	}
+	return data, nil
+}
+
+// host returns the GitHub host of the app, applying the default
+func (a *App) host() string {
+	if a.Host == "" {
+		return DefaultHost
+	}
+	return a.Host
+}
+
+// installationToken exchanges an app JWT for an installation access token
+func (a *App) installationToken() (token, error) {
+	jwt, err := a.jwt()
+	if err != nil {
+		return token{}, err
+	}
+	id := a.InstallationID
+	if id == 0 {
+		if a.Repo == "" {
+			return token{}, fmt.Errorf("installation ID not configured and no repository to look it up from")
+		}
+		var installation struct {
+			ID int64 `json:"id"`
+		}
+		if err := a.request("GET", fmt.Sprintf("%s/repos/%s/installation", a.apiBase(), a.Repo), jwt, &installation); err != nil {
+			return token{}, fmt.Errorf("failed to find the installation for %s: %w", a.Repo, err)
+		}
+		id = installation.ID
+	}
+	var data struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := a.request("POST", fmt.Sprintf("%s/app/installations/%d/access_tokens", a.apiBase(), id), jwt, &data); err != nil {
+		return token{}, fmt.Errorf("failed to create an installation token: %w", err)
+	}
+	return token{value: data.Token, expires: data.ExpiresAt}, nil
+}
+
+// jwt signs the short-lived RS256 token that authenticates the app itself
+func (a *App) jwt() (string, error) {
+	key, err := parsePrivateKey(a.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now
+	if a.now != nil {
+		now = a.now
+	}
+	issued := now().Add(-time.Minute)
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iat": issued.Unix(),
+		"exp": issued.Add(10 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.ID, 10),
+	})
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// parsePrivateKey decodes a PKCS#1 or PKCS#8 RSA key
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("GitHub App private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// apiBase returns the REST API root, applying the default
+func (a *App) apiBase() string {
+	if a.APIBase == "" {
+		return DefaultAPIBase
+	}
+	return a.APIBase
+}
+
+// request sends an app-authenticated API request and decodes the JSON response into out
+func (a *App) request(method, url, jwt string, out interface{}) error {
+	req, err := http.NewRequest(method, url, bytes.NewReader(nil))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}(resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
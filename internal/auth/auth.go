@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHost is the GitHub host used when a repository does not name another one
+const DefaultHost = "github.com"
+
+// Provider resolves GitHub tokens per host, trying in order the environment, the configured
+// token, a GitHub App installation token, the gh CLI hosts file and the git credential helper
+type Provider struct {
+	// Token is the configured token for hosts without an entry in Tokens
+	Token string
+	// Tokens maps a host name to its configured token
+	Tokens map[string]string
+	// App mints installation tokens for App.Host when set
+	App *App
+
+	// getenv, ghConfigDir and credentialFill are replaced in tests
+	getenv         func(string) string
+	ghConfigDir    func() string
+	credentialFill func(host string) (string, error)
+
+	mu     sync.Mutex
+	tokens map[string]token
+}
+
+// token is a resolved token and where it came from
+type token struct {
+	value   string
+	source  string
+	expires time.Time
+}
+
+// source looks up a token for a host; an empty value means the source has none
+type source struct {
+	name   string
+	lookup func(host string) (token, error)
+}
+
+var (
+	currentMu sync.RWMutex
+	current   = &Provider{}
+)
+
+// Current returns the token provider configured for this process
+func Current() *Provider {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// SetCurrent replaces the token provider used by all GitHub requests in this process
+func SetCurrent(p *Provider) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = p
+}
+
+// TokenFor returns the GitHub token for host, or "" when no source has one. Tokens are
+// looked up once per host; installation tokens are renewed shortly before they expire.
+func (p *Provider) TokenFor(host string) (string, error) {
+	if host == "" {
+		host = DefaultHost
+	}
+	return p.resolve(host, host, p.sources())
+}
+
+// ForgeToken returns the token for a GitLab or Gitea host from the first of envVars that is
+// set, the configured tokens or the git credential helper; GitHub variables are never used
+func (p *Provider) ForgeToken(host string, envVars ...string) (string, error) {
+	var sources []source
+	for _, name := range envVars {
+		name := name
+		sources = append(sources, source{name: name, lookup: func(string) (token, error) {
+			return token{value: strings.TrimSpace(p.env(name))}, nil
+		}})
+	}
+	sources = append(sources,
+		source{name: "the configuration file", lookup: func(host string) (token, error) {
+			return token{value: p.Tokens[host]}, nil
+		}},
+		p.credentialSource(),
+	)
+	return p.resolve("forge:"+host, host, sources)
+}
+
+// resolve returns the cached token under key or looks it up from sources
+func (p *Provider) resolve(key, host string, sources []source) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t, ok := p.tokens[key]; ok && (t.expires.IsZero() || time.Until(t.expires) > time.Minute) {
+		return t.value, nil
+	}
+	if p.tokens == nil {
+		p.tokens = map[string]token{}
+	}
+	for _, s := range sources {
+		t, err := s.lookup(host)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", s.name, err)
+		}
+		if t.value == "" {
+			continue
+		}
+		t.source = s.name
+		p.tokens[key] = t
+		log.Printf("Authenticating to %s with a token from %s\n", host, s.name)
+		return t.value, nil
+	}
+	p.tokens[key] = token{}
+	return "", nil
+}
+
+// sources lists the token sources in lookup order
+func (p *Provider) sources() []source {
+	// GitHub Enterprise Server hosts read GH_ENTERPRISE_TOKEN and GITHUB_ENTERPRISE_TOKEN
+	// first, like the gh CLI; GITHUB_TOKEN and GH_TOKEN apply to any host
+	env := func(name, enterprise string) source {
+		return source{name: name, lookup: func(host string) (token, error) {
+			if host != DefaultHost {
+				if t := strings.TrimSpace(p.env(enterprise)); t != "" {
+					return token{value: t}, nil
+				}
+			}
+			return token{value: strings.TrimSpace(p.env(name))}, nil
+		}}
+	}
+	sources := []source{
+		env("GITHUB_TOKEN", "GITHUB_ENTERPRISE_TOKEN"),
+		env("GH_TOKEN", "GH_ENTERPRISE_TOKEN"),
+		{name: "the configuration file", lookup: func(host string) (token, error) {
+			if t, ok := p.Tokens[host]; ok {
+				return token{value: t}, nil
+			}
+			return token{value: p.Token}, nil
+		}},
+		{name: "the GitHub App", lookup: func(host string) (token, error) {
+			if p.App == nil || !strings.EqualFold(p.App.host(), host) {
+				return token{}, nil
+			}
+			return p.App.installationToken()
+		}},
+	}
+	return append(sources,
+		source{name: "the gh CLI", lookup: func(host string) (token, error) {
+			return token{value: ghHostsToken(p.ghDir(), host)}, nil
+		}},
+		p.credentialSource(),
+	)
+}
+
+// credentialSource asks the git credential helper
+func (p *Provider) credentialSource() source {
+	return source{name: "the git credential helper", lookup: func(host string) (token, error) {
+		fill := p.credentialFill
+		if fill == nil {
+			fill = gitCredentialFill
+		}
+		value, err := fill(host)
+		if err != nil {
+			// No helper or no stored credential: not an error, just no token
+			return token{}, nil
+		}
+		return token{value: value}, nil
+	}}
+}
+
+// env reads an environment variable
+func (p *Provider) env(name string) string {
+	if p.getenv != nil {
+		return p.getenv(name)
+	}
+	return os.Getenv(name)
+}
+
+// ghDir returns the gh CLI configuration directory
+func (p *Provider) ghDir() string {
+	if p.ghConfigDir != nil {
+		return p.ghConfigDir()
+	}
+	if dir := p.env("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if dir := p.env("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gh")
+}
+
+// ghHostsToken reads the oauth_token of host from the gh CLI hosts.yml. Tokens that gh
+// keeps in the system keyring are not visible here.
+func ghHostsToken(dir, host string) string {
+	if dir == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "hosts.yml"))
+	if err != nil {
+		return ""
+	}
+	inHost := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			inHost = strings.TrimSuffix(strings.TrimSpace(line), ":") == host
+			continue
+		}
+		if !inHost {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && key == "oauth_token" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// gitCredentialFill asks the configured git credential helper for the password of
+// https://host without ever prompting
+func gitCredentialFill(host string) (string, error) {
+	cmd := exec.Command("git", "-c", "core.askPass=", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host))
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if value, ok := strings.CutPrefix(line, "password="); ok {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", fmt.Errorf("no password returned for %s", host)
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestProvider returns a provider reading only the given environment and gh directory
+func newTestProvider(env map[string]string, ghDir string) *Provider {
+	return &Provider{
+		getenv:         func(name string) string { return env[name] },
+		ghConfigDir:    func() string { return ghDir },
+		credentialFill: func(string) (string, error) { return "", errors.New("no helper") },
+	}
+}
+
+func TestTokenForPrefersEnvironment(t *testing.T) {
+	p := newTestProvider(map[string]string{"GH_TOKEN": "gh-env", "GITHUB_TOKEN": "github-env"}, "")
+	p.Token = "configured"
+	if got, err := p.TokenFor(""); err != nil || got != "github-env" {
+		t.Errorf("TokenFor = %q, %v; want github-env", got, err)
+	}
+
+	p = newTestProvider(map[string]string{"GH_TOKEN": "gh-env"}, "")
+	p.Token = "configured"
+	if got, _ := p.TokenFor(""); got != "gh-env" {
+		t.Errorf("TokenFor = %q, want gh-env", got)
+	}
+
+	p = newTestProvider(nil, "")
+	p.Token = "configured"
+	if got, _ := p.TokenFor(""); got != "configured" {
+		t.Errorf("TokenFor = %q, want configured", got)
+	}
+}
+
+func TestTokenForReadsGHHostsAndCredentialHelper(t *testing.T) {
+	dir := t.TempDir()
+	hosts := "github.com:\n    user: octocat\n    oauth_token: gho_public\n    git_protocol: https\nghe.example.com:\n    oauth_token: \"gho_enterprise\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p := newTestProvider(nil, dir)
+	if got, _ := p.TokenFor("github.com"); got != "gho_public" {
+		t.Errorf("TokenFor(github.com) = %q, want gho_public", got)
+	}
+	if got, _ := p.TokenFor("ghe.example.com"); got != "gho_enterprise" {
+		t.Errorf("TokenFor(ghe.example.com) = %q, want gho_enterprise", got)
+	}
+
+	p.credentialFill = func(host string) (string, error) { return "helper-" + host, nil }
+	if got, _ := p.TokenFor("other.example.com"); got != "helper-other.example.com" {
+		t.Errorf("TokenFor(other.example.com) = %q, want the credential helper token", got)
+	}
+}
+
+func TestAppInstallationToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") || strings.Count(r.Header.Get("Authorization"), ".") != 2 {
+			t.Errorf("expected an app JWT, got %q", r.Header.Get("Authorization"))
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/jfrog/jfrog-cli/installation":
+			_ = json.NewEncoder(w).Encode(map[string]int64{"id": 42})
+		case r.Method == "POST" && r.URL.Path == "/app/installations/42/access_tokens":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"token": "ghs_installation", "expires_at": expires})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	p := newTestProvider(nil, "")
+	p.App = &App{ID: 7, PrivateKey: pemKey, Repo: "jfrog/jfrog-cli", APIBase: srv.URL}
+	if got, err := p.TokenFor(""); err != nil || got != "ghs_installation" {
+		t.Fatalf("TokenFor = %q, %v; want ghs_installation", got, err)
+	}
+	if got := p.tokens[DefaultHost]; got.source != "the GitHub App" || !got.expires.Equal(expires) {
+		t.Errorf("cached token %+v, want the app token expiring at %s", got, expires)
+	}
+}
+
+func TestForgeTokenIgnoresGitHubVariables(t *testing.T) {
+	p := newTestProvider(map[string]string{"GITHUB_TOKEN": "github-env"}, "")
+	if got, _ := p.ForgeToken("gitlab.example.com", "GITLAB_TOKEN"); got != "" {
+		t.Errorf("ForgeToken = %q, want no token", got)
+	}
+
+	p = newTestProvider(map[string]string{"GITHUB_TOKEN": "github-env", "GITLAB_TOKEN": "gitlab-env"}, "")
+	p.Tokens = map[string]string{"gitlab.example.com": "configured"}
+	if got, _ := p.ForgeToken("gitlab.example.com", "GITLAB_TOKEN"); got != "gitlab-env" {
+		t.Errorf("ForgeToken = %q, want gitlab-env", got)
+	}
+	if got, _ := p.TokenFor("github.com"); got != "github-env" {
+		t.Errorf("TokenFor = %q, want github-env", got)
+	}
+}
@@ -0,0 +1,116 @@
+package chain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bhanurp/jfrm/internal/registry"
+)
+
+// Repo is a repository taking part in the release chain
+type Repo struct {
+	Module string
+	Slug   string
+	// DependsOn lists the modules of other chain repositories this one requires
+	DependsOn []string
+}
+
+// Name returns the short repository name, e.g. "jfrog-cli-core"
+func (r Repo) Name() string {
+	if i := strings.LastIndex(r.Slug, "/"); i >= 0 {
+		return r.Slug[i+1:]
+	}
+	return r.Slug
+}
+
+// FromRegistry builds the chain from registered repositories and their dependency graph
+// (module → required registered modules), as computed by registry.Dependencies
+func FromRegistry(repos []registry.Repository, graph map[string][]string) []Repo {
+	out := make([]Repo, 0, len(repos))
+	for _, r := range repos {
+		out = append(out, Repo{Module: r.Module, Slug: r.Slug, DependsOn: graph[r.Module]})
+	}
+	return out
+}
+
+// Order sorts repos so that every repository comes after the ones it depends on.
+// Ties keep the input order; dependencies on modules outside repos are ignored.
+func Order(repos []Repo) ([]Repo, error) {
+	index := make(map[string]int, len(repos))
+	for i, r := range repos {
+		index[r.Module] = i
+	}
+	pending := make([]int, len(repos))
+	dependents := make([][]int, len(repos))
+	for i, r := range repos {
+		for _, dep := range r.DependsOn {
+			if j, ok := index[dep]; ok && j != i {
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	done := make([]bool, len(repos))
+	ordered := make([]Repo, 0, len(repos))
+	for len(ordered) < len(repos) {
+		next := -1
+		for i := range repos {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			var cycle []string
+			for i, r := range repos {
+				if !done[i] {
+					cycle = append(cycle, r.Module)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+		}
+		done[next] = true
+		ordered = append(ordered, repos[next])
+		for _, d := range dependents[next] {
+			pending[d]--
+		}
+	}
+	return ordered, nil
+}
+
+// Downstream returns the repo identified by module (or short name) and every repo that
+// depends on it, directly or transitively, keeping the order of repos
+func Downstream(repos []Repo, from string) ([]Repo, error) {
+	selected := make(map[string]bool)
+	for _, r := range repos {
+		if r.Module == from || r.Name() == from || r.Slug == from {
+			selected[r.Module] = true
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("%s is not part of the release chain", from)
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, r := range repos {
+			if selected[r.Module] {
+				continue
+			}
+			for _, dep := range r.DependsOn {
+				if selected[dep] {
+					selected[r.Module] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	var out []Repo
+	for _, r := range repos {
+		if selected[r.Module] {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,122 @@
+package chain
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/registry"
+)
+
+// jfrogRepos mirrors the dependency graph of the JFrog repositories
+var jfrogRepos = FromRegistry(registry.Default().Repositories(), map[string][]string{
+	"github.com/jfrog/build-info-go":         {"github.com/jfrog/gofrog"},
+	"github.com/jfrog/jfrog-client-go":       {"github.com/jfrog/build-info-go", "github.com/jfrog/gofrog"},
+	"github.com/jfrog/jfrog-cli-core/v2":     {"github.com/jfrog/build-info-go", "github.com/jfrog/gofrog", "github.com/jfrog/jfrog-client-go"},
+	"github.com/jfrog/jfrog-cli-artifactory": {"github.com/jfrog/jfrog-cli-core/v2", "github.com/jfrog/jfrog-client-go"},
+	"github.com/jfrog/jfrog-cli-security":    {"github.com/jfrog/jfrog-cli-core/v2", "github.com/jfrog/jfrog-client-go"},
+	"github.com/jfrog/jfrog-cli":             {"github.com/jfrog/jfrog-cli-artifactory", "github.com/jfrog/jfrog-cli-core/v2", "github.com/jfrog/jfrog-cli-security"},
+})
+
+func TestOrder(t *testing.T) {
+	reversed := make([]Repo, len(jfrogRepos))
+	for i, r := range jfrogRepos {
+		reversed[len(jfrogRepos)-1-i] = r
+	}
+	ordered, err := Order(reversed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, r := range ordered {
+		names = append(names, r.Name())
+	}
+	want := "gofrog,build-info-go,jfrog-client-go,jfrog-cli-core,jfrog-cli-security,jfrog-cli-artifactory,jfrog-cli"
+	if got := strings.Join(names, ","); got != want {
+		t.Fatalf("unexpected order: %s", got)
+	}
+
+	cyclic := []Repo{{Module: "a", DependsOn: []string{"b"}}, {Module: "b", DependsOn: []string{"a"}}}
+	if _, err := Order(cyclic); err == nil {
+		t.Fatalf("expected cycle error")
+	}
+}
+
+func TestDownstream(t *testing.T) {
+	got, err := Downstream(jfrogRepos, "jfrog-cli-core")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 || got[0].Name() != "jfrog-cli-core" {
+		t.Fatalf("unexpected downstream repos: %+v", got)
+	}
+	if _, err := Downstream(jfrogRepos, "unknown"); err == nil {
+		t.Fatalf("expected error for unknown repo")
+	}
+}
+
+type fakeRunner struct {
+	merged  bool
+	bumped  map[string]map[string]string
+	release map[string]string
+}
+
+func (f *fakeRunner) Bump(repo Repo, versions map[string]string) (string, int, error) {
+	f.bumped[repo.Module] = versions
+	if len(versions) == 0 {
+		return "", 0, nil
+	}
+	return "bump", 42, nil
+}
+
+func (f *fakeRunner) Merged(Repo, int) (bool, error) { return f.merged, nil }
+
+func (f *fakeRunner) Release(repo Repo) (string, bool, error) {
+	return f.release[repo.Module], true, nil
+}
+
+func (f *fakeRunner) Available(string, string) (bool, error) { return true, nil }
+
+func TestOrchestratorResumes(t *testing.T) {
+	repos := []Repo{
+		{Module: "m/b", Slug: "o/b", DependsOn: []string{"m/a"}},
+		{Module: "m/a", Slug: "o/a"},
+	}
+	path := filepath.Join(t.TempDir(), "state.json")
+	state, err := LoadState(path, repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := &fakeRunner{bumped: map[string]map[string]string{}, release: map[string]string{"m/a": "v1.1.0", "m/b": "v2.0.1"}}
+	o := &Orchestrator{Repos: repos, State: state, Runner: runner}
+
+	if err := o.Run(); !errors.Is(err, ErrWaiting) {
+		t.Fatalf("expected to wait for PR merge, got %v", err)
+	}
+	if runner.bumped["m/b"]["m/a"] != "v1.1.0" {
+		t.Fatalf("expected b to be bumped to a@v1.1.0, got %v", runner.bumped["m/b"])
+	}
+
+	// Resume from the saved state once the PR is merged
+	state, err = LoadState(path, repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := state.Get("m/b"); st.Step != StepPROpen || st.PR != 42 {
+		t.Fatalf("unexpected persisted state: %+v", st)
+	}
+	runner.merged = true
+	runner.bumped = map[string]map[string]string{}
+	o = &Orchestrator{Repos: repos, State: state, Runner: runner, Wait: true, Timeout: time.Second, sleep: func(time.Duration) {}}
+	if err := o.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(runner.bumped) != 0 {
+		t.Fatalf("expected no repeated bumps, got %v", runner.bumped)
+	}
+	if st := state.Get("m/b"); st.Step != StepDone || st.Version != "v2.0.1" {
+		t.Fatalf("unexpected final state: %+v", st)
+	}
+}
@@ -0,0 +1,147 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrWaiting is returned when the chain cannot advance until something external happens
+// (a PR is merged or a release reaches the module proxy). Rerunning resumes from the state file.
+var ErrWaiting = errors.New("release chain is waiting")
+
+// Runner performs the side effects of each step for a repository
+type Runner interface {
+	// Bump requires the given module versions and opens a PR; pr is 0 when nothing changed
+	Bump(repo Repo, versions map[string]string) (branch string, pr int, err error)
+	// Merged reports whether the PR has been merged
+	Merged(repo Repo, pr int) (bool, error)
+	// Release publishes the next version; released is false when there was nothing to release,
+	// in which case version is the current latest release
+	Release(repo Repo) (version string, released bool, err error)
+	// Available reports whether the version can be fetched from the module proxy
+	Available(module, version string) (bool, error)
+}
+
+// Orchestrator advances repositories through the release chain in dependency order
+type Orchestrator struct {
+	Repos  []Repo
+	State  *State
+	Runner Runner
+	// Wait polls for PR merges and proxy availability instead of stopping with ErrWaiting
+	Wait         bool
+	PollInterval time.Duration
+	Timeout      time.Duration
+
+	sleep func(time.Duration)
+}
+
+// Run advances every repository as far as possible, saving the state after each step
+func (o *Orchestrator) Run() error {
+	ordered, err := Order(o.Repos)
+	if err != nil {
+		return err
+	}
+	for _, repo := range ordered {
+		st := o.State.Get(repo.Module)
+		if st == nil {
+			return fmt.Errorf("no state for %s", repo.Module)
+		}
+		for st.Step != StepDone {
+			if err := o.advance(repo, st); err != nil {
+				return err
+			}
+			st.UpdatedAt = time.Now()
+			if err := o.State.Save(); err != nil {
+				return fmt.Errorf("failed to save state: %w", err)
+			}
+		}
+		log.Printf("✅ %s done (%s)\n", repo.Name(), st.Version)
+	}
+	return nil
+}
+
+// advance performs the next step for a repository
+func (o *Orchestrator) advance(repo Repo, st *RepoState) error {
+	switch st.Step {
+	case StepPending, "":
+		versions := o.upstreamVersions(repo)
+		branch, pr, err := o.Runner.Bump(repo, versions)
+		if err != nil {
+			return fmt.Errorf("%s: failed to bump dependencies: %w", repo.Name(), err)
+		}
+		st.Branch, st.PR = branch, pr
+		if pr == 0 {
+			st.Step = StepMerged
+			st.Note = "no dependency changes"
+			return nil
+		}
+		st.Step = StepPROpen
+	case StepPROpen:
+		err := o.waitFor(fmt.Sprintf("%s: PR #%d to be merged", repo.Name(), st.PR), func() (bool, error) {
+			return o.Runner.Merged(repo, st.PR)
+		})
+		if err != nil {
+			return err
+		}
+		st.Step = StepMerged
+	case StepMerged:
+		ver, released, err := o.Runner.Release(repo)
+		if err != nil {
+			return fmt.Errorf("%s: failed to release: %w", repo.Name(), err)
+		}
+		st.Version = ver
+		if !released {
+			st.Step = StepDone
+			st.Note = "nothing to release"
+			return nil
+		}
+		st.Step = StepReleased
+	case StepReleased:
+		err := o.waitFor(fmt.Sprintf("%s@%s to reach the module proxy", repo.Module, st.Version), func() (bool, error) {
+			return o.Runner.Available(repo.Module, st.Version)
+		})
+		if err != nil {
+			return err
+		}
+		st.Step = StepDone
+	default:
+		return fmt.Errorf("%s: unknown step %q in state file", repo.Name(), st.Step)
+	}
+	return nil
+}
+
+// upstreamVersions returns the released versions of the chain repositories repo depends on
+func (o *Orchestrator) upstreamVersions(repo Repo) map[string]string {
+	versions := make(map[string]string)
+	for _, dep := range repo.DependsOn {
+		if st := o.State.Get(dep); st != nil && st.Step == StepDone && st.Version != "" {
+			versions[dep] = st.Version
+		}
+	}
+	return versions
+}
+
+// waitFor checks cond once, or polls it until Timeout when Wait is set
+func (o *Orchestrator) waitFor(what string, cond func() (bool, error)) error {
+	sleep := o.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	deadline := time.Now().Add(o.Timeout)
+	for {
+		ok, err := cond()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		if !o.Wait || !time.Now().Before(deadline) {
+			return fmt.Errorf("%w for %s", ErrWaiting, what)
+		}
+		log.Printf("Waiting for %s...\n", what)
+		sleep(o.PollInterval)
+	}
+}
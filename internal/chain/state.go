@@ -0,0 +1,84 @@
+package chain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Step is the progress of a repository through the release chain
+type Step string
+
+const (
+	StepPending  Step = "pending"
+	StepPROpen   Step = "pr-open"
+	StepMerged   Step = "merged"
+	StepReleased Step = "released"
+	StepDone     Step = "done"
+)
+
+// RepoState records how far a repository has progressed
+type RepoState struct {
+	Module    string    `json:"module"`
+	Slug      string    `json:"slug"`
+	Step      Step      `json:"step"`
+	Branch    string    `json:"branch,omitempty"`
+	PR        int       `json:"pr,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// State is the resumable progress of a release chain run, persisted as JSON
+type State struct {
+	Repos []*RepoState `json:"repos"`
+	path  string
+}
+
+// LoadState reads the state file at path, adding entries for repos it does not track yet
+func LoadState(path string, repos []Repo) (*State, error) {
+	s := &State{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read state %s: %w", path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("failed to parse state %s: %w", path, err)
+		}
+	}
+	for _, r := range repos {
+		if s.Get(r.Module) == nil {
+			s.Repos = append(s.Repos, &RepoState{Module: r.Module, Slug: r.Slug, Step: StepPending})
+		}
+	}
+	return s, nil
+}
+
+// Get returns the state of a module, or nil when it is not tracked
+func (s *State) Get(module string) *RepoState {
+	for _, r := range s.Repos {
+		if r.Module == module {
+			return r
+		}
+	}
+	return nil
+}
+
+// Save writes the state file
+func (s *State) Save() error {
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
@@ -0,0 +1,267 @@
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/releasenotes"
+)
+
+// DefaultFile is the conventional changelog location
+const DefaultFile = "CHANGELOG.md"
+
+// ChangeTypes lists the Keep-a-Changelog change types in display order
+var ChangeTypes = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// Categories maps PRs to Keep-a-Changelog change types; unmatched PRs are "Changed"
+var Categories = []releasenotes.Category{
+	{Title: "Security", Labels: []string{"security"}},
+	{Title: "Removed", Labels: []string{"removal"}},
+	{Title: "Deprecated", Labels: []string{"deprecation"}},
+	{Title: "Changed", Labels: []string{"breaking change"}, Breaking: true},
+	{Title: "Added", Labels: []string{"new feature", "feature request"}, CommitTypes: []string{"feat"}},
+	{Title: "Fixed", Labels: []string{"bug"}, CommitTypes: []string{"fix"}},
+}
+
+const header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+`
+
+var (
+	versionHeading    = regexp.MustCompile(`^##\s+\[?([^\]\s]+)\]?`)
+	unreleasedHeading = regexp.MustCompile(`(?i)^##\s+\[?unreleased\]?`)
+	typeHeading       = regexp.MustCompile(`^###\s+(\S+)`)
+	linkReference     = regexp.MustCompile(`^\[([^\]]+)\]:\s*\S+`)
+)
+
+// Changelog is a Keep-a-Changelog document. It is edited line by line so that
+// existing entries, formatting and link references are preserved.
+type Changelog struct {
+	lines []string
+}
+
+// Release describes a version section to insert
+type Release struct {
+	// Version is written without a "v" prefix, e.g. "1.3.0"
+	Version string
+	Date    time.Time
+	// Entries maps a change type such as "Added" to its bullet items (without the leading "- ")
+	Entries map[string][]string
+	// CompareURL is the repository compare base, e.g. "https://github.com/owner/repo/compare";
+	// when set, link references for the new version and [Unreleased] are maintained
+	CompareURL string
+	// PreviousTag and Tag are the git tags compared in the version link
+	PreviousTag string
+	Tag         string
+}
+
+// Parse reads an existing changelog
+func Parse(data []byte) *Changelog {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	return &Changelog{lines: strings.Split(strings.TrimRight(text, "\n"), "\n")}
+}
+
+// New returns an empty changelog with the standard Keep-a-Changelog header
+func New() *Changelog {
+	return Parse([]byte(header))
+}
+
+// Bytes renders the changelog
+func (c *Changelog) Bytes() []byte {
+	return []byte(strings.Join(c.lines, "\n") + "\n")
+}
+
+// HasVersion reports whether a section for the version already exists
+func (c *Changelog) HasVersion(ver string) bool {
+	ver = strings.TrimPrefix(ver, "v")
+	for _, line := range c.lines {
+		if m := versionHeading.FindStringSubmatch(line); m != nil && strings.TrimPrefix(m[1], "v") == ver {
+			return true
+		}
+	}
+	return false
+}
+
+// AddRelease inserts a section for the release below [Unreleased], moving any
+// unreleased entries into it, and updates the link references
+func (c *Changelog) AddRelease(r Release) error {
+	if c.HasVersion(r.Version) {
+		return fmt.Errorf("changelog already contains version %s", r.Version)
+	}
+	links := c.linkBlockStart()
+
+	entries := make(map[string][]string)
+	insertAt := links
+	if u := c.find(unreleasedHeading); u >= 0 {
+		end := c.sectionEnd(u, links)
+		for t, items := range parseEntries(c.lines[u+1 : end]) {
+			entries[t] = append(entries[t], items...)
+		}
+		c.lines = append(c.lines[:u+1], append([]string{""}, c.lines[end:]...)...)
+		links = c.linkBlockStart()
+		insertAt = u + 2
+	} else if first := c.find(versionHeading); first >= 0 {
+		insertAt = first
+	}
+	for t, items := range r.Entries {
+		entries[t] = append(entries[t], items...)
+	}
+
+	var section []string
+	if insertAt > 0 && strings.TrimSpace(c.lines[insertAt-1]) != "" {
+		section = append(section, "")
+	}
+	section = append(section, fmt.Sprintf("## [%s] - %s", r.Version, r.Date.Format("2006-01-02")))
+	for _, t := range orderedTypes(entries) {
+		section = append(section, "", "### "+t, "")
+		for _, item := range entries[t] {
+			section = append(section, "- "+item)
+		}
+	}
+	if len(entries) == 0 {
+		section = append(section, "", "No notable changes.")
+	}
+	section = append(section, "")
+	c.lines = insertLines(c.lines, insertAt, section)
+
+	if r.CompareURL != "" {
+		c.updateLinks(r)
+	}
+	return nil
+}
+
+// Entries converts merged PRs and dependency updates into change type entries
+func Entries(prs []github.PullRequest, updates []deps.Update, repoURL string) map[string][]string {
+	entries := make(map[string][]string)
+	notes := releasenotes.Build("", "", prs, nil, releasenotes.Options{Categories: Categories})
+	for _, s := range notes.Sections {
+		t := s.Title
+		if t == "Other Changes" {
+			t = "Changed"
+		}
+		for _, pr := range s.PullRequests {
+			ref := fmt.Sprintf("#%d", pr.Number)
+			if repoURL != "" {
+				ref = fmt.Sprintf("[#%d](%s/pull/%d)", pr.Number, repoURL, pr.Number)
+			}
+			entries[t] = append(entries[t], fmt.Sprintf("%s (%s)", pr.Title, ref))
+		}
+	}
+	for _, u := range updates {
+		entries["Changed"] = append(entries["Changed"], fmt.Sprintf("Updated `%s` from %s to %s", u.Module, u.From, u.To))
+	}
+	return entries
+}
+
+func (c *Changelog) find(re *regexp.Regexp) int {
+	for i, line := range c.lines {
+		if re.MatchString(line) {
+			return i
+		}
+	}
+	return -1
+}
+
+// linkBlockStart returns the index of the trailing block of link references, or len(lines)
+func (c *Changelog) linkBlockStart() int {
+	start := len(c.lines)
+	for i := len(c.lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(c.lines[i])
+		if linkReference.MatchString(line) {
+			start = i
+		} else if line != "" {
+			break
+		}
+	}
+	return start
+}
+
+// sectionEnd returns the index of the line following the section starting at start
+func (c *Changelog) sectionEnd(start, limit int) int {
+	for i := start + 1; i < limit; i++ {
+		if strings.HasPrefix(c.lines[i], "## ") {
+			return i
+		}
+	}
+	return limit
+}
+
+func (c *Changelog) updateLinks(r Release) {
+	compare := strings.TrimSuffix(r.CompareURL, "/")
+	newLink := fmt.Sprintf("[%s]: %s/%s...%s", r.Version, compare, r.PreviousTag, r.Tag)
+	if r.PreviousTag == "" {
+		newLink = fmt.Sprintf("[%s]: %s", r.Version, strings.TrimSuffix(compare, "/compare")+"/releases/tag/"+r.Tag)
+	}
+	unreleased := fmt.Sprintf("[Unreleased]: %s/%s...HEAD", compare, r.Tag)
+
+	start := c.linkBlockStart()
+	for i := start; i < len(c.lines); i++ {
+		m := linkReference.FindStringSubmatch(strings.TrimSpace(c.lines[i]))
+		if m != nil && strings.EqualFold(m[1], "unreleased") {
+			c.lines[i] = unreleased
+			c.lines = insertLines(c.lines, i+1, []string{newLink})
+			return
+		}
+	}
+	if start == len(c.lines) {
+		for len(c.lines) > 0 && strings.TrimSpace(c.lines[len(c.lines)-1]) == "" {
+			c.lines = c.lines[:len(c.lines)-1]
+		}
+		c.lines = append(c.lines, "", unreleased, newLink)
+		return
+	}
+	c.lines = insertLines(c.lines, start, []string{unreleased, newLink})
+}
+
+// parseEntries collects "- item" lines grouped by their "### Type" heading
+func parseEntries(lines []string) map[string][]string {
+	entries := make(map[string][]string)
+	current := "Changed"
+	for _, line := range lines {
+		if m := typeHeading.FindStringSubmatch(line); m != nil {
+			current = m[1]
+			continue
+		}
+		if item, ok := strings.CutPrefix(strings.TrimSpace(line), "- "); ok {
+			entries[current] = append(entries[current], item)
+		}
+	}
+	return entries
+}
+
+func orderedTypes(entries map[string][]string) []string {
+	var out []string
+	known := make(map[string]bool)
+	for _, t := range ChangeTypes {
+		known[t] = true
+		if len(entries[t]) > 0 {
+			out = append(out, t)
+		}
+	}
+	var extra []string
+	for t, items := range entries {
+		if !known[t] && len(items) > 0 {
+			extra = append(extra, t)
+		}
+	}
+	sort.Strings(extra)
+	return append(out, extra...)
+}
+
+func insertLines(lines []string, at int, add []string) []string {
+	out := make([]string, 0, len(lines)+len(add))
+	out = append(out, lines[:at]...)
+	out = append(out, add...)
+	return append(out, lines[at:]...)
+}
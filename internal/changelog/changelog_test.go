@@ -0,0 +1,101 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/github"
+)
+
+const existing = `# Changelog
+
+Some intro.
+
+## [Unreleased]
+
+### Fixed
+
+- Manual fix entry
+
+## [1.2.0] - 2025-01-10
+
+### Added
+
+- Old feature
+
+[Unreleased]: https://github.com/owner/repo/compare/v1.2.0...HEAD
+[1.2.0]: https://github.com/owner/repo/compare/v1.1.0...v1.2.0
+`
+
+func TestAddRelease(t *testing.T) {
+	c := Parse([]byte(existing))
+	err := c.AddRelease(Release{
+		Version:     "1.3.0",
+		Date:        time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		Entries:     map[string][]string{"Added": {"New command (#5)"}, "Fixed": {"Crash (#6)"}},
+		CompareURL:  "https://github.com/owner/repo/compare",
+		PreviousTag: "v1.2.0",
+		Tag:         "v1.3.0",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# Changelog
+
+Some intro.
+
+## [Unreleased]
+
+## [1.3.0] - 2025-02-01
+
+### Added
+
+- New command (#5)
+
+### Fixed
+
+- Manual fix entry
+- Crash (#6)
+
+## [1.2.0] - 2025-01-10
+
+### Added
+
+- Old feature
+
+[Unreleased]: https://github.com/owner/repo/compare/v1.3.0...HEAD
+[1.3.0]: https://github.com/owner/repo/compare/v1.2.0...v1.3.0
+[1.2.0]: https://github.com/owner/repo/compare/v1.1.0...v1.2.0
+`
+	if got := string(c.Bytes()); got != want {
+		t.Fatalf("unexpected changelog:\n%s", got)
+	}
+	if err := c.AddRelease(Release{Version: "v1.3.0"}); err == nil {
+		t.Fatalf("expected error for duplicate version")
+	}
+}
+
+func TestNewAndEntries(t *testing.T) {
+	prs := []github.PullRequest{
+		{Number: 1, Title: "feat: add flag"},
+		{Number: 2, Title: "Refactor internals"},
+	}
+	entries := Entries(prs, []deps.Update{{Module: "github.com/jfrog/gofrog", From: "v1.0.0", To: "v1.1.0"}}, "https://github.com/owner/repo")
+	if got := entries["Added"]; len(got) != 1 || got[0] != "feat: add flag ([#1](https://github.com/owner/repo/pull/1))" {
+		t.Fatalf("unexpected Added entries: %v", got)
+	}
+	if got := entries["Changed"]; len(got) != 2 || !strings.HasPrefix(got[1], "Updated `github.com/jfrog/gofrog`") {
+		t.Fatalf("unexpected Changed entries: %v", got)
+	}
+
+	c := New()
+	if err := c.AddRelease(Release{Version: "0.1.0", Entries: entries, CompareURL: "https://github.com/owner/repo/compare", Tag: "v0.1.0"}); err != nil {
+		t.Fatal(err)
+	}
+	out := string(c.Bytes())
+	if !strings.Contains(out, "## [Unreleased]\n\n## [0.1.0]") || !strings.HasSuffix(out, "[0.1.0]: https://github.com/owner/repo/releases/tag/v0.1.0\n") {
+		t.Fatalf("unexpected new changelog:\n%s", out)
+	}
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bhanurp/jfrm/internal/httpcache"
+	"github.com/urfave/cli/v2"
+)
+
+// Cache creates the cache command managing the on-disk HTTP cache
+func Cache() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "Manage the on-disk cache of module proxy and GitHub responses",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "clean",
+				Usage: "Remove all cached responses",
+				Action: func(c *cli.Context) error {
+					dir, err := httpcache.Dir()
+					if err != nil {
+						return err
+					}
+					if err := httpcache.Clean(dir); err != nil {
+						return fmt.Errorf("failed to clean cache: %w", err)
+					}
+					_, err = fmt.Fprintf(c.App.Writer, "Removed %s\n", dir)
+					return err
+				},
+			},
+			{
+				Name:  "dir",
+				Usage: "Print the cache directory",
+				Action: func(c *cli.Context) error {
+					dir, err := httpcache.Dir()
+					if err != nil {
+						return err
+					}
+					_, err = fmt.Fprintln(c.App.Writer, dir)
+					return err
+				},
+			},
+		},
+	}
+}
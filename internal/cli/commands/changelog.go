@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/changelog"
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/urfave/cli/v2"
+)
+
+// changelogFlag enables CHANGELOG maintenance on commands that produce a release commit
+func changelogFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "changelog",
+		Usage: fmt.Sprintf("Add the new version to this Keep-a-Changelog file (e.g. %s)", changelog.DefaultFile),
+	}
+}
+
+// addChangelogRelease inserts a section for next, tagged as d names it, into the changelog
+// content (a new changelog is started when data is nil). changed is false when the version is
+// already present.
+func addChangelogRelease(data []byte, repo, previousTag, next string, d forge.Discovery, entries map[string][]string) (out []byte, changed bool, err error) {
+	c := changelog.New()
+	if data != nil {
+		c = changelog.Parse(data)
+	}
+	if c.HasVersion(next) {
+		log.Printf("Changelog already contains %s; leaving it unchanged", next)
+		return data, false, nil
+	}
+	repoURL := forge.Current().RepoURL(repo)
+	err = c.AddRelease(changelog.Release{
+		Version:     next,
+		Date:        time.Now(),
+		Entries:     entries,
+		CompareURL:  repoURL + "/compare",
+		PreviousTag: previousTag,
+		Tag:         d.Tag(next),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return c.Bytes(), true, nil
+}
+
+// changelogEntries converts merged PRs and dependency updates into changelog entries linking to repo
+func changelogEntries(repo string, prs []github.PullRequest, updates []deps.Update) map[string][]string {
+	return changelog.Entries(prs, updates, forge.Current().RepoURL(repo))
+}
+
+// updateLocalChangelog adds the next version to the changelog file in the working tree
+func updateLocalChangelog(path, repo, tag, next string, d forge.Discovery, entries map[string][]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	out, changed, err := addChangelogRelease(data, repo, tag, next, d, entries)
+	if err != nil || !changed {
+		return err
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	log.Printf("Updated %s for %s", path, next)
+	return nil
+}
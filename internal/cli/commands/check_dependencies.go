@@ -1,10 +1,15 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
-	"log"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/resolver"
 	"github.com/urfave/cli/v2"
 )
 
@@ -14,29 +19,149 @@ func CheckDependencies() *cli.Command {
 		Name:    "check-dependencies",
 		Aliases: []string{"cd"},
 		Usage:   "Check current dependency status",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Output format: text, table, json or yaml",
+				Value:   "text",
+			},
+			&cli.IntFlag{
+				Name:  "exit-code",
+				Usage: "Exit with this code when updates are available (0 always exits successfully)",
+			},
+		},
 		Action: func(c *cli.Context) error {
+			format := strings.ToLower(c.String("format"))
+			switch format {
+			case "text", "table", "json", "yaml":
+			default:
+				return fmt.Errorf("unsupported format %q; expected text, table, json or yaml", format)
+			}
+
 			dependencies, err := deps.GetDependencies()
 			if err != nil {
 				return fmt.Errorf("failed to read go.mod: %w", err)
 			}
 
-			fmt.Println("Current Dependencies:")
-			fmt.Println("=====================")
-			for mod, currentVer := range dependencies {
-				if deps.IsAllowedDependency(mod) {
-					latestVer, err := deps.GetLatestModuleVersion(mod)
-					if err != nil {
-						log.Printf("Failed to get latest version for %s: %v", mod, err)
-						continue
-					}
-					status := "✅ Up to date"
-					if deps.IsNewerVersion(currentVer, latestVer) {
-						status = fmt.Sprintf("🔄 Update available: %s → %s", currentVer, latestVer)
-					}
-					fmt.Printf("%s: %s (%s)\n", mod, currentVer, status)
-				}
+			statuses := resolveStatuses(c, dependencies)
+			if err := writeDependencyStatus(c.App.Writer, statuses, format); err != nil {
+				return err
+			}
+			if n := deps.Outdated(statuses); n > 0 && c.Int("exit-code") != 0 {
+				return cli.Exit(fmt.Sprintf("%d dependency update(s) available", n), c.Int("exit-code"))
 			}
 			return nil
 		},
 	}
 }
+
+// resolveStatuses resolves the managed dependencies concurrently through the shared
+// resolver and compares them against their latest versions
+func resolveStatuses(c *cli.Context, dependencies map[string]string) []deps.Status {
+	res := resolver.Shared()
+	res.Resolve(c.Context, managedModules(dependencies))
+	statuses := deps.Check(dependencies, res.LatestFunc(c.Context))
+	if c.Bool("offline") {
+		for i := range statuses {
+			statuses[i].Stale = true
+		}
+	}
+	return statuses
+}
+
+// managedModules returns the managed modules among the dependencies
+func managedModules(dependencies map[string]string) []string {
+	var modules []string
+	for mod := range dependencies {
+		if deps.IsAllowedDependency(mod) {
+			modules = append(modules, mod)
+		}
+	}
+	return modules
+}
+
+// writeDependencyStatus renders the dependency statuses in the requested format
+func writeDependencyStatus(w io.Writer, statuses []deps.Status, format string) error {
+	switch format {
+	case "json":
+		if statuses == nil {
+			statuses = []deps.Status{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(statuses)
+	case "yaml":
+		return writeDependencyStatusYAML(w, statuses)
+	}
+
+	if anyStale(statuses) {
+		fmt.Fprintln(w, "⚠️  Offline: latest versions come from local caches and may be stale")
+	}
+	if format == "table" {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "MODULE\tCURRENT\tLATEST\tSTATUS\tUPDATE\tPSEUDO\tERROR")
+		for _, s := range statuses {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\t%s\n", s.Module, s.Current, s.Latest, s.Status, s.UpdateType, s.PseudoVersion, s.Error)
+		}
+		return tw.Flush()
+	}
+
+	fmt.Fprintln(w, "Current Dependencies:")
+	fmt.Fprintln(w, "=====================")
+	for _, s := range statuses {
+		var status string
+		switch s.Status {
+		case deps.StatusOutdated:
+			status = fmt.Sprintf("🔄 Update available: %s → %s", s.Current, s.Latest)
+		case deps.StatusError:
+			status = fmt.Sprintf("❌ Failed to get latest version: %s", s.Error)
+		default:
+			status = "✅ Up to date"
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s (%s)\n", s.Module, s.Current, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// anyStale reports whether any status was read from a local cache
+func anyStale(statuses []deps.Status) bool {
+	for _, s := range statuses {
+		if s.Stale {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDependencyStatusYAML renders the statuses as a YAML sequence with the same keys as the JSON output
+func writeDependencyStatusYAML(w io.Writer, statuses []deps.Status) error {
+	if len(statuses) == 0 {
+		_, err := fmt.Fprintln(w, "[]")
+		return err
+	}
+	for _, s := range statuses {
+		fields := []struct{ key, value string }{
+			{"module", strconv.Quote(s.Module)},
+			{"current", strconv.Quote(s.Current)},
+			{"latest", strconv.Quote(s.Latest)},
+			{"status", s.Status},
+			{"updateType", strconv.Quote(s.UpdateType)},
+			{"pseudoVersion", strconv.FormatBool(s.PseudoVersion)},
+			{"error", strconv.Quote(s.Error)},
+			{"stale", strconv.FormatBool(s.Stale)},
+		}
+		for i, f := range fields {
+			prefix := "  "
+			if i == 0 {
+				prefix = "- "
+			}
+			if _, err := fmt.Fprintf(w, "%s%s: %s\n", prefix, f.key, f.value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+)
+
+func TestWriteDependencyStatusYAML(t *testing.T) {
+	statuses := []deps.Status{
+		{Module: "github.com/jfrog/gofrog", Current: "v1.7.5", Latest: "v1.7.6", Status: deps.StatusOutdated, UpdateType: "patch"},
+	}
+	var buf bytes.Buffer
+	if err := writeDependencyStatus(&buf, statuses, "yaml"); err != nil {
+		t.Fatal(err)
+	}
+	want := `- module: "github.com/jfrog/gofrog"
+  current: "v1.7.5"
+  latest: "v1.7.6"
+  status: outdated
+  updateType: "patch"
+  pseudoVersion: false
+  error: ""
+  stale: false
+`
+	if buf.String() != want {
+		t.Fatalf("unexpected yaml output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeDependencyStatus(&buf, nil, "json"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Fatalf("expected an empty JSON array, got %q", buf.String())
+	}
+}
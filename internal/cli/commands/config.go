@@ -0,0 +1,209 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/auth"
+	"github.com/bhanurp/jfrm/internal/config"
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/httpcache"
+	"github.com/bhanurp/jfrm/internal/httpclient"
+	"github.com/bhanurp/jfrm/internal/registry"
+	"github.com/bhanurp/jfrm/internal/resolver"
+	"github.com/bhanurp/jfrm/internal/version"
+	"github.com/urfave/cli/v2"
+)
+
+// Setup loads the configuration before any command runs and installs the repository
+// registry, forge, GitHub host and token provider, HTTP client and version resolver shared by all commands
+func Setup(c *cli.Context) error {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+	reg, err := cfg.Registry()
+	if err != nil {
+		return err
+	}
+	registry.SetCurrent(reg)
+	remoteHost, _ := deps.GetRepoHost()
+	kind, forgeHost, err := forgeKind(c, cfg, remoteHost)
+	if err != nil {
+		return err
+	}
+	// Only a GitHub remote names the GitHub host; other forges keep the github.com defaults
+	// so that GitHub tokens are never sent to them
+	githubRemote := ""
+	if kind == forge.KindGitHub {
+		githubRemote = remoteHost
+	}
+	if err := setupGitHub(c, cfg, githubRemote); err != nil {
+		return err
+	}
+	if err := setupForge(cfg, kind, forgeHost); err != nil {
+		return err
+	}
+	if c.Bool("debug") {
+		client := httpclient.New()
+		client.Debug = true
+		httpclient.SetDefault(client)
+	}
+	if err := setupHTTPCache(c); err != nil {
+		return err
+	}
+	lookup := deps.GetLatestModuleInfoContext
+	if c.Bool("offline") {
+		log.Println("Offline mode: versions and GitHub data come from local caches and may be stale")
+		lookup = offlineLookup
+	}
+	resolver.SetShared(resolver.New(lookup, c.Int("parallelism"), c.Duration("request-timeout")))
+	return nil
+}
+
+// setupGitHub selects the GitHub host from --github-host, the configuration or remoteHost (empty
+// unless the remote is on GitHub), and installs it together with the token provider built from
+// the configuration
+func setupGitHub(c *cli.Context, cfg *config.Config, remoteHost string) error {
+	gh := cfg.GitHub
+	if name := c.String("github-host"); name != "" {
+		gh.Host = name
+	}
+	if apiURL := c.String("github-api-url"); apiURL != "" {
+		gh.APIURL = apiURL
+	}
+	host := gh.Endpoints(remoteHost)
+	github.SetCurrentHost(host)
+
+	var repo string
+	if app := gh.App; app != nil && app.InstallationID == 0 {
+		repo, _ = deps.GetRepoName()
+	}
+	p, err := gh.Auth(host, repo)
+	if err != nil {
+		return fmt.Errorf("invalid github configuration: %w", err)
+	}
+	auth.SetCurrent(p)
+	return nil
+}
+
+// forgeKind selects the forge kind from --forge, the configuration or the remote host, and
+// returns it with the forge host
+func forgeKind(c *cli.Context, cfg *config.Config, remoteHost string) (string, string, error) {
+	kind := c.String("forge")
+	if kind == "" {
+		kind = cfg.Forge.Type
+	}
+	host := cfg.Forge.Host
+	if host == "" {
+		host = remoteHost
+	}
+	if kind == "" {
+		kind = forge.Detect(host)
+	}
+	kind, err := forge.ParseKind(kind)
+	if err != nil {
+		return "", "", err
+	}
+	if kind != forge.KindGitHub && host == "" {
+		return "", "", fmt.Errorf("the %s host is unknown; set forge.host in the configuration", kind)
+	}
+	return kind, host, nil
+}
+
+// setupForge installs the forge of the given kind on host
+func setupForge(cfg *config.Config, kind, host string) error {
+	f, err := forge.New(kind, host, cfg.Forge.APIURL)
+	if err != nil {
+		return err
+	}
+	if cfg.Forge.Token != "" && kind != forge.KindGitHub {
+		p := auth.Current()
+		if p.Tokens == nil {
+			p.Tokens = map[string]string{}
+		}
+		p.Tokens[host] = cfg.Forge.Token
+	}
+	forge.SetCurrent(f)
+	return nil
+}
+
+// forgeToken returns the forge token needed for writes, failing when no source provides one
+func forgeToken(purpose string) (string, error) {
+	f := forge.Current()
+	token, err := f.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve a %s token: %w", f.Kind(), err)
+	}
+	if token != "" {
+		return token, nil
+	}
+	switch f.Kind() {
+	case forge.KindGitLab:
+		return "", fmt.Errorf("no GitLab token found (required %s); set GITLAB_TOKEN or configure forge.token", purpose)
+	case forge.KindGitea:
+		return "", fmt.Errorf("no Gitea token found (required %s); set GITEA_TOKEN or configure forge.token", purpose)
+	}
+	return "", fmt.Errorf("no GitHub token found (required %s); set GITHUB_TOKEN or GH_TOKEN, run gh auth login, or configure github.token or github.app", purpose)
+}
+
+// setupHTTPCache routes HTTP requests through the on-disk cache unless --no-cache is set
+func setupHTTPCache(c *cli.Context) error {
+	offline := c.Bool("offline")
+	if c.Bool("no-cache") {
+		if offline {
+			return fmt.Errorf("--offline needs the HTTP cache; it cannot be combined with --no-cache")
+		}
+		return nil
+	}
+	if t, ok := http.DefaultTransport.(*httpcache.Transport); ok {
+		t.Offline = offline
+		return nil
+	}
+	dir, err := httpcache.Dir()
+	if err != nil {
+		if offline {
+			return fmt.Errorf("offline mode unavailable: %w", err)
+		}
+		log.Printf("HTTP cache disabled: %v", err)
+		return nil
+	}
+	t := httpcache.New(filepath.Join(dir, "http"), http.DefaultTransport)
+	t.Offline = offline
+	http.DefaultTransport = t
+	return nil
+}
+
+// offlineLookup resolves the latest version from the HTTP cache, falling back to the
+// versions downloaded into the local module cache
+func offlineLookup(ctx context.Context, module string) (string, time.Time, error) {
+	v, published, err := deps.GetLatestModuleInfoContext(ctx, module)
+	if err == nil {
+		return v, published, nil
+	}
+	v, published, cacheErr := deps.GetCachedModuleInfo(module)
+	if cacheErr != nil {
+		return "", time.Time{}, fmt.Errorf("%v; %w", err, cacheErr)
+	}
+	return v, published, nil
+}
+
+// loadConfig reads the configuration file selected by the global --config flag
+func loadConfig(c *cli.Context) (*config.Config, error) {
+	return config.Load(c.String("config"))
+}
+
+// loadReleaseRules returns the configured release rules
+func loadReleaseRules(c *cli.Context) (version.Rules, error) {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return version.Rules{}, err
+	}
+	return cfg.Release.Rules()
+}
@@ -3,9 +3,10 @@ package commands
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/bhanurp/jfrm/internal/deps"
-	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/forge"
 	"github.com/bhanurp/jfrm/internal/report"
 	"github.com/urfave/cli/v2"
 )
@@ -16,16 +17,31 @@ func GenerateReport() *cli.Command {
 		Name:    "generate-report",
 		Aliases: []string{"gr"},
 		Usage:   "Generate a dependency update report",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "output",
 				Aliases: []string{"o"},
-				Usage:   "Output file path for the report",
-				Value:   "dependency-report.md",
+				Usage:   "Output file path for the report (default: dependency-report with the extension of the format)",
 			},
-		},
+			reportFormatFlag(),
+			templateDirFlag(),
+			historyDirFlag(),
+		}, versionFlags()...),
 		Action: func(c *cli.Context) error {
+			format := c.String("format")
+			ext, err := report.Extension(format)
+			if err != nil {
+				return err
+			}
 			outputFile := c.String("output")
+			if outputFile == "" {
+				outputFile = "dependency-report" + ext
+			}
+
+			rules, err := loadReleaseRules(c)
+			if err != nil {
+				return fmt.Errorf("invalid release rules: %w", err)
+			}
 
 			// Get repository information
 			repo, err := deps.GetRepoName()
@@ -39,20 +55,69 @@ func GenerateReport() *cli.Command {
 				return fmt.Errorf("failed to read go.mod: %w", err)
 			}
 
+			templateDir, err := reportTemplateDir(c)
+			if err != nil {
+				return err
+			}
+
 			// Get latest release information
-			tag, _, releasedTime, err := github.GetLatestReleaseVersionAndCommitSHA(repo)
+			rel, _, err := latestRelease(c, repo)
 			if err != nil {
-				return fmt.Errorf("failed to get latest release: %w", err)
+				return err
 			}
+			tag, releasedTime := rel.Tag, rel.PublishedAt
 
 			// Get merged PRs since last release
-			prs, err := github.GetAllMergedPRs(repo, releasedTime)
+			prs, err := forge.Current().MergedChanges(repo, releasedTime)
 			if err != nil {
 				log.Printf("Error fetching merged PRs: %v\n", err)
 			}
 
+			decision := rules.Evaluate(prs)
+			nextVersion, err := versionPlan(c, decision.Bump).Next(rel.Version)
+			if err != nil {
+				return fmt.Errorf("failed to compute next version: %w", err)
+			}
+
 			// Generate the report
-			return report.GenerateDependencyReport(repo, dependencies, prs, tag, outputFile)
+			statuses := resolveStatuses(c, dependencies)
+			r := report.New(report.KindDependency, repo, statuses, prs, tag, nextVersion, decision)
+			r.LatestReleaseDate = releasedTime
+			r.Stale = r.Stale || c.Bool("offline")
+			if err := saveReportSnapshot(c, r); err != nil {
+				return err
+			}
+			return report.GenerateDependencyReport(r, format, outputFile, templateDir)
 		},
 	}
 }
+
+// reportFormatFlag selects the format of the generated report
+func reportFormatFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "format",
+		Aliases: []string{"f"},
+		Usage:   "Report format: " + strings.Join(report.Formats, ", "),
+		Value:   "markdown",
+	}
+}
+
+// templateDirFlag selects a directory of report templates overriding the built-in ones
+func templateDirFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "template-dir",
+		Usage: "Directory with report templates (dependency.md.tmpl, dry-run.md.tmpl, dependency.html.tmpl, ...) overriding the built-in ones",
+	}
+}
+
+// reportTemplateDir returns the template directory from --template-dir or the configuration
+func reportTemplateDir(c *cli.Context) (string, error) {
+	if dir := c.String("template-dir"); dir != "" {
+		return dir, nil
+	}
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return "", err
+	}
+	return cfg.Report.TemplateDir, nil
+}
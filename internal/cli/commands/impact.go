@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/impact"
+	"github.com/bhanurp/jfrm/internal/registry"
+	"github.com/urfave/cli/v2"
+)
+
+// Impact creates the impact command
+func Impact() *cli.Command {
+	return &cli.Command{
+		Name:      "impact",
+		Usage:     "List the registered repositories that need a bump after a release",
+		ArgsUsage: "<module>@<version>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Output format: text or json",
+				Value:   "text",
+			},
+			&cli.StringFlag{
+				Name:  "clone-cache",
+				Usage: "Read go.mod files from clones kept in this directory instead of the GitHub contents API",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			module, ver, ok := strings.Cut(c.Args().First(), "@")
+			if !ok || module == "" || ver == "" {
+				return fmt.Errorf("expected <module>@<version>, e.g. github.com/jfrog/jfrog-client-go@v1.50.0")
+			}
+			format := strings.ToLower(c.String("format"))
+			if format != "text" && format != "json" {
+				return fmt.Errorf("unsupported format %q; expected text or json", format)
+			}
+
+			repos := registry.Current().Repositories()
+			requirements := make(map[string]map[string]string, len(repos))
+			errs := make(map[string]error)
+			for _, repo := range repos {
+				if repo.Module == module {
+					continue
+				}
+				data, err := readDefaultBranchGoMod(repo, c.String("clone-cache"))
+				if err == nil {
+					requirements[repo.Module], err = deps.ParseDependencies(data)
+				}
+				if err != nil {
+					log.Printf("Failed to read go.mod of %s: %v", repo.Slug, err)
+					errs[repo.Module] = err
+				}
+			}
+
+			results := impact.Analyze(module, ver, repos, requirements, errs)
+			return writeImpact(c.App.Writer, module, ver, results, format)
+		},
+	}
+}
+
+// readDefaultBranchGoMod returns the go.mod of a repository's base branch
+func readDefaultBranchGoMod(repo registry.Repository, cloneCache string) ([]byte, error) {
+	if cloneCache == "" {
+		data, _, err := forge.Current().GetFile(repo.Slug, "go.mod", repo.Base())
+		if err == nil && data == nil {
+			err = fmt.Errorf("go.mod not found on %s", repo.Base())
+		}
+		return data, err
+	}
+
+	dir := filepath.Join(cloneCache, repo.Name())
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(cloneCache, 0755); err != nil {
+			return nil, err
+		}
+		if _, err := runIn(cloneCache, "git", "clone", "--no-checkout", forge.Current().RepoURL(repo.Slug)+".git", repo.Name()); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := runIn(dir, "git", "fetch", "origin", repo.Base()); err != nil {
+		return nil, err
+	}
+	out, err := runIn(dir, "git", "show", fmt.Sprintf("origin/%s:go.mod", repo.Base()))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
+
+// writeImpact renders the impact results
+func writeImpact(w io.Writer, module, ver string, results []impact.Result, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Module       string          `json:"module"`
+			Version      string          `json:"version"`
+			Repositories []impact.Result `json:"repositories"`
+		}{module, ver, results})
+	}
+
+	if _, err := fmt.Fprintf(w, "Impact of %s@%s:\n", module, ver); err != nil {
+		return err
+	}
+	for _, r := range results {
+		var line string
+		switch {
+		case r.Status == impact.StatusUnknown:
+			line = fmt.Sprintf("❓ %s: unknown, go.mod could not be read: %s", r.Slug, r.Error)
+		case r.Status == impact.StatusOutdated:
+			line = fmt.Sprintf("🔄 %s: requires %s → needs %s", r.Slug, r.Current, ver)
+		case r.Status == impact.StatusTransitive:
+			line = fmt.Sprintf("🔁 %s: needs a bump after %s", r.Slug, strings.Join(r.Via, ", "))
+		case r.Status == impact.StatusUpToDate:
+			line = fmt.Sprintf("✅ %s: up to date (%s)", r.Slug, r.Current)
+		default:
+			line = fmt.Sprintf("➖ %s: does not depend on %s", r.Slug, module)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/config"
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/registry"
+	"github.com/bhanurp/jfrm/internal/version"
+	"github.com/urfave/cli/v2"
+)
+
+// nextVersionResult is the machine-readable output of the next-version command
+type nextVersionResult struct {
+	Repository   string            `json:"repository"`
+	Current      string            `json:"current"`
+	Next         string            `json:"next"`
+	Bump         string            `json:"bump"`
+	PreRelease   bool              `json:"preRelease"`
+	PullRequests []justifyingPRRef `json:"pullRequests"`
+}
+
+// justifyingPRRef identifies a PR that determined the bump
+type justifyingPRRef struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// NextVersion creates the next-version command
+func NextVersion() *cli.Command {
+	return &cli.Command{
+		Name:    "next-version",
+		Aliases: []string{"nv"},
+		Usage:   "Print the next version based on PRs merged since the latest release",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Output format: text, json or env (KEY=VALUE lines for $GITHUB_OUTPUT)",
+				Value:   "text",
+			},
+			&cli.StringFlag{
+				Name:  "tag",
+				Usage: "Use this tag as the latest release instead of discovering it",
+			},
+		}, versionFlags()...),
+		Action: func(c *cli.Context) error {
+			format := strings.ToLower(c.String("format"))
+			if format != "text" && format != "json" && format != "env" {
+				return fmt.Errorf("unsupported format %q; expected text, json or env", format)
+			}
+
+			cfg, err := loadConfig(c)
+			if err != nil {
+				return err
+			}
+			rules, err := cfg.Release.Rules()
+			if err != nil {
+				return fmt.Errorf("invalid release rules: %w", err)
+			}
+
+			repo, err := deps.GetRepoName()
+			if err != nil {
+				return fmt.Errorf("failed to detect repository: %w", err)
+			}
+			discovery, err := releaseDiscovery(c, cfg, repo)
+			if err != nil {
+				return err
+			}
+
+			tag, releasedTime, err := resolveBaseRelease(repo, c.String("tag"), discovery)
+			if err != nil {
+				return err
+			}
+
+			prs, err := forge.Current().MergedChanges(repo, releasedTime)
+			if err != nil {
+				return fmt.Errorf("failed to fetch merged PRs: %w", err)
+			}
+
+			decision := rules.Evaluate(prs)
+			next, err := versionPlan(c, decision.Bump).Next(discovery.Version(tag))
+			if err != nil {
+				return fmt.Errorf("failed to compute next version: %w", err)
+			}
+
+			result := nextVersionResult{
+				Repository: repo,
+				Current:    tag,
+				Next:       next,
+				Bump:       decision.Bump.String(),
+				PreRelease: version.IsPreRelease(next),
+			}
+			for _, r := range decision.Justification() {
+				result.PullRequests = append(result.PullRequests, justifyingPRRef{Number: r.PR.Number, Title: r.PR.Title, Reason: r.Source})
+			}
+			return writeNextVersion(c.App.Writer, result, format)
+		},
+	}
+}
+
+// resolveBaseRelease determines the tag the next version is computed from and when it was released
+func resolveBaseRelease(repo, tag string, d forge.Discovery) (string, time.Time, error) {
+	switch {
+	case tag != "" && d.Mode == forge.DiscoverGit:
+		released, err := deps.LocalTagDate(tag)
+		return tag, released, err
+	case tag != "":
+		released, err := forge.Current().ReleaseDate(repo, tag)
+		if err != nil {
+			log.Printf("No release for %s (%v); using the local tag date", tag, err)
+			released, err = deps.LocalTagDate(tag)
+		}
+		return tag, released, err
+	}
+	latest, released, _, err := forge.Discover(forge.Current(), repo, d)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to get latest release: %w", err)
+	}
+	return latest, released, nil
+}
+
+// latestRelease discovers the latest release of repo with its tag resolved to a commit. When
+// the forge API is unavailable, e.g. offline with nothing cached, the latest tag of the local
+// clone is used instead. The discovery is returned for naming the next release tag.
+func latestRelease(c *cli.Context, repo string) (*forge.ReleaseInfo, forge.Discovery, error) {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return nil, forge.Discovery{}, err
+	}
+	d, err := releaseDiscovery(c, cfg, repo)
+	if err != nil {
+		return nil, d, err
+	}
+	rel, err := forge.LatestReleaseInfo(forge.Current(), repo, d)
+	if err != nil {
+		return nil, d, fmt.Errorf("failed to get latest release: %w", err)
+	}
+	return rel, d, nil
+}
+
+// releaseDiscovery layers the discovery flags over the configured release discovery of repo
+func releaseDiscovery(c *cli.Context, cfg *config.Config, repo string) (forge.Discovery, error) {
+	d := cfg.Release.ReleaseDiscovery(registry.Current().BaseBranch(repo))
+	if mode := strings.TrimSpace(c.String("discovery")); mode != "" {
+		d.Mode = strings.ToLower(mode)
+	}
+	if prefix := c.String("tag-prefix"); prefix != "" {
+		d.Prefix = prefix
+	}
+	if c.Bool("include-pre-releases") {
+		d.PreReleases = true
+	}
+	return d, d.Validate()
+}
+
+// writeNextVersion renders the result in the requested format
+func writeNextVersion(w io.Writer, r nextVersionResult, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "env":
+		var numbers []string
+		for _, pr := range r.PullRequests {
+			numbers = append(numbers, fmt.Sprintf("%d", pr.Number))
+		}
+		_, err := fmt.Fprintf(w, "current_version=%s\nnext_version=%s\nbump=%s\nprerelease=%t\npull_requests=%s\n",
+			r.Current, r.Next, r.Bump, r.PreRelease, strings.Join(numbers, ","))
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Next version: %s (%s bump from %s)\n", r.Next, r.Bump, r.Current); err != nil {
+		return err
+	}
+	for _, pr := range r.PullRequests {
+		if _, err := fmt.Fprintf(w, "- PR #%d %s (%s)\n", pr.Number, pr.Title, pr.Reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// versionFlags returns the flags shared by commands that predict the next version
+func versionFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "pre-release",
+			Usage: fmt.Sprintf("Plan a pre-release on the given channel (%s)", strings.Join(version.PreReleaseChannels, ", ")),
+		},
+		&cli.BoolFlag{
+			Name:  "promote",
+			Usage: "Promote the latest pre-release to its final version",
+		},
+		&cli.StringFlag{
+			Name:  "discovery",
+			Usage: "Where to discover the latest release: release (forge releases, then tags), tag (forge tags) or git (local tags on the base branch)",
+		},
+		&cli.StringFlag{
+			Name:  "tag-prefix",
+			Usage: "Part of release tags before the version (default: v)",
+		},
+		&cli.BoolFlag{
+			Name:  "include-pre-releases",
+			Usage: "Let a pre-release be discovered as the latest release",
+		},
+	}
+}
+
+// versionPlan builds the version plan from the shared flags
+func versionPlan(c *cli.Context, bump version.Bump) version.Plan {
+	return version.Plan{
+		Bump:       bump,
+		PreRelease: strings.ToLower(strings.TrimSpace(c.String("pre-release"))),
+		Promote:    c.Bool("promote"),
+	}
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteNextVersion(t *testing.T) {
+	r := nextVersionResult{
+		Repository:   "owner/repo",
+		Current:      "v1.2.3",
+		Next:         "1.3.0",
+		Bump:         "minor",
+		PullRequests: []justifyingPRRef{{Number: 7, Title: "feat: x", Reason: `conventional commit "feat"`}, {Number: 9}},
+	}
+	var buf bytes.Buffer
+	if err := writeNextVersion(&buf, r, "env"); err != nil {
+		t.Fatal(err)
+	}
+	want := "current_version=v1.2.3\nnext_version=1.3.0\nbump=minor\nprerelease=false\npull_requests=7,9\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected env output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeNextVersion(&buf, r, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded nextVersionResult
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if decoded.Next != "1.3.0" || len(decoded.PullRequests) != 2 {
+		t.Fatalf("unexpected json result: %+v", decoded)
+	}
+}
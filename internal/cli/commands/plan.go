@@ -0,0 +1,261 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/plan"
+	"github.com/bhanurp/jfrm/internal/version"
+	"github.com/urfave/cli/v2"
+)
+
+// Plan creates the plan command
+func Plan() *cli.Command {
+	return &cli.Command{
+		Name:  "plan",
+		Usage: "Compute a dependency update and write it as a JSON plan for apply",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Plan file to write",
+				Value:   plan.DefaultFile,
+			},
+			&cli.BoolFlag{
+				Name:    "create-pr",
+				Aliases: []string{"p"},
+				Usage:   "Push the branch and create a pull request when the plan is applied",
+			},
+			&cli.StringFlag{
+				Name:  "remote",
+				Usage: "Base in form <remote>/<branch> (default: upstream/<base branch of the repository>, e.g. upstream/dev)",
+			},
+			&cli.StringFlag{
+				Name:  "new-branch",
+				Usage: "Override the generated branch name (e.g., update-dependencies-1.2.3)",
+			},
+			changelogFlag(),
+		}, versionFlags()...),
+		Action: func(c *cli.Context) error {
+			rules, err := loadReleaseRules(c)
+			if err != nil {
+				return fmt.Errorf("invalid release rules: %w", err)
+			}
+			vplan := versionPlan(c, version.BumpNone)
+			if err := vplan.Validate(); err != nil {
+				return err
+			}
+
+			repo, err := deps.GetRepoName()
+			if err != nil {
+				return fmt.Errorf("failed to detect repository: %w", err)
+			}
+			baseRemote, baseBranch, err := resolveBaseRef(repo, strings.TrimSpace(c.String("remote")))
+			if err != nil {
+				return err
+			}
+
+			dependencies, err := deps.GetDependencies()
+			if err != nil {
+				return fmt.Errorf("failed to read go.mod: %w", err)
+			}
+			latest := make(map[string]string)
+			for _, s := range resolveStatuses(c, dependencies) {
+				switch s.Status {
+				case deps.StatusError:
+					log.Printf("Skipping %s: %s", s.Module, s.Error)
+				case deps.StatusOutdated:
+					latest[s.Module] = s.Latest
+				}
+			}
+			updates := dependencyUpdates(dependencies, latest)
+
+			rel, discovery, err := latestRelease(c, repo)
+			if err != nil {
+				return err
+			}
+			tag, releasedTime := rel.Tag, rel.PublishedAt
+			prs, err := forge.Current().MergedChanges(repo, releasedTime)
+			if err != nil {
+				log.Printf("Error fetching merged PRs: %v\n", err)
+			}
+			decision := rules.Evaluate(prs)
+			vplan.Bump = decision.Bump
+			next, err := vplan.Next(rel.Version)
+			if err != nil {
+				return fmt.Errorf("failed to compute next version: %w", err)
+			}
+
+			p := &plan.Plan{
+				Repository:    repo,
+				BaseRemote:    baseRemote,
+				BaseBranch:    baseBranch,
+				Branch:        buildBranchName(c.String("new-branch"), next),
+				LatestRelease: tag,
+				NextVersion:   next,
+				TagPrefix:     discovery.Prefix,
+				Updates:       updates,
+				CreatePR:      c.Bool("create-pr"),
+				PullRequest: plan.PullRequest{
+					Title: fmt.Sprintf("chore(%s): update dependencies to latest versions", next),
+					Body:  pullRequestBody(updates),
+				},
+				CreatedAt: time.Now(),
+			}
+			if changelogFile := strings.TrimSpace(c.String("changelog")); changelogFile != "" {
+				p.Changelog = changelogFile
+				p.ChangelogEntries = changelogEntries(repo, releasablePRs(prs, decision), updates)
+			}
+
+			output := c.String("output")
+			if err := p.Save(output); err != nil {
+				return fmt.Errorf("failed to write plan: %w", err)
+			}
+			_, err = fmt.Fprintf(c.App.Writer, "Plan for %s written to %s: %d update(s) on %s from %s/%s, next version %s\n",
+				repo, output, len(updates), p.Branch, baseRemote, baseBranch, next)
+			return err
+		},
+	}
+}
+
+// Apply creates the apply command
+func Apply() *cli.Command {
+	return &cli.Command{
+		Name:  "apply",
+		Usage: "Execute a plan step by step, resuming after the last completed step",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "plan",
+				Usage: "Plan file to apply",
+				Value: plan.DefaultFile,
+			},
+			&cli.BoolFlag{
+				Name:  "restart",
+				Usage: "Ignore the recorded progress and apply the plan from the first step",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			planPath := c.String("plan")
+			p, err := plan.Load(planPath)
+			if err != nil {
+				return err
+			}
+			statePath := plan.StatePath(planPath)
+			if c.Bool("restart") {
+				if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to reset state: %w", err)
+				}
+			}
+			state, err := plan.LoadState(statePath, p)
+			if err != nil {
+				return err
+			}
+
+			if p.CreatePR {
+				if _, err := forgeToken("for PR creation"); err != nil {
+					return err
+				}
+			}
+
+			err = state.Apply(planSteps(p, state), func(name string, skipped bool) {
+				if skipped {
+					log.Printf("⏭️  %s (already done)", name)
+				} else {
+					log.Printf("▶️  %s", name)
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("%w; rerun apply to resume", err)
+			}
+			if state.PR != 0 {
+				log.Printf("✅ Plan applied: PR #%d", state.PR)
+			} else {
+				log.Printf("✅ Plan applied on branch %s", p.Branch)
+			}
+			return nil
+		},
+	}
+}
+
+// planSteps returns the resumable steps that apply p
+func planSteps(p *plan.Plan, state *plan.State) []plan.Step {
+	files := []string{"go.mod", "go.sum"}
+	steps := []plan.Step{
+		{Name: "checkout", Run: func() error {
+			_ = exec.Command("git", "fetch", p.BaseRemote, p.BaseBranch).Run()
+			return deps.GitExec("checkout", "-B", p.Branch, fmt.Sprintf("%s/%s", p.BaseRemote, p.BaseBranch))
+		}},
+		{Name: "update-modules", Run: func() error {
+			for _, u := range p.Updates {
+				if err := deps.UpdateDependency(u.Module, u.From, u.To, false); err != nil {
+					return fmt.Errorf("failed to update %s: %w", u.Module, err)
+				}
+			}
+			return nil
+		}},
+		{Name: "tidy", Run: func() error {
+			return exec.Command("go", "mod", "tidy").Run()
+		}},
+	}
+	if p.Changelog != "" {
+		files = append(files, p.Changelog)
+		steps = append(steps, plan.Step{Name: "changelog", Run: func() error {
+			return updateLocalChangelog(p.Changelog, p.Repository, p.LatestRelease, p.NextVersion, forge.Discovery{Prefix: p.TagPrefix}, p.ChangelogEntries)
+		}})
+	}
+	steps = append(steps, plan.Step{Name: "commit", Run: func() error {
+		if err := deps.GitExec(append([]string{"add"}, files...)...); err != nil {
+			return err
+		}
+		if exec.Command("git", "diff", "--cached", "--quiet").Run() == nil {
+			log.Println("Nothing to commit")
+			return nil
+		}
+		return deps.GitExec("commit", "-m", p.PullRequest.Title)
+	}})
+	if !p.CreatePR {
+		return steps
+	}
+	return append(steps,
+		plan.Step{Name: "push", Run: func() error {
+			return deps.GitExec("push", "origin", p.Branch, "--force-with-lease")
+		}},
+		plan.Step{Name: "create-pr", Run: func() error {
+			existing, err := forge.Current().FindOpenChange(p.Repository, p.Branch, p.BaseBranch)
+			if err != nil {
+				return err
+			}
+			if existing != 0 {
+				log.Printf("PR #%d already exists for %s", existing, p.Branch)
+				state.PR = existing
+				return nil
+			}
+			state.PR, err = forge.Current().CreateChange(p.Repository, forge.Change{
+				Branch: p.Branch,
+				Base:   p.BaseBranch,
+				Title:  p.PullRequest.Title,
+				Body:   p.PullRequest.Body,
+			})
+			return err
+		}},
+	)
+}
+
+// pullRequestBody describes the dependency updates of a plan
+func pullRequestBody(updates []deps.Update) string {
+	if len(updates) == 0 {
+		return "This PR updates Go dependencies to the latest versions."
+	}
+	var b strings.Builder
+	b.WriteString("This PR updates Go dependencies to the latest versions:\n\n")
+	for _, u := range updates {
+		fmt.Fprintf(&b, "- `%s`: %s → %s\n", u.Module, u.From, u.To)
+	}
+	return b.String()
+}
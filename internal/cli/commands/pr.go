@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/registry"
+	"github.com/urfave/cli/v2"
+)
+
+// PR creates the pr command
+func PR() *cli.Command {
+	return &cli.Command{
+		Name:  "pr",
+		Usage: "Inspect pull requests",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "status",
+				Usage:     "Show the checks, review decision and mergeability of a PR, exiting non-zero when checks failed",
+				ArgsUsage: "[number]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "branch",
+						Usage: "Find the open PR of this branch when no number is given (default: the current branch)",
+					},
+					&cli.BoolFlag{
+						Name:  "wait",
+						Usage: "Poll until the checks finish and mergeability is known, or the PR is closed",
+					},
+					&cli.DurationFlag{
+						Name:  "poll-interval",
+						Usage: "Delay between polls when --wait is set",
+						Value: 30 * time.Second,
+					},
+					&cli.DurationFlag{
+						Name:  "timeout",
+						Usage: "Maximum time to wait when --wait is set",
+						Value: 30 * time.Minute,
+					},
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "Output format: text or json",
+						Value:   "text",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					format := strings.ToLower(c.String("format"))
+					if format != "text" && format != "json" {
+						return fmt.Errorf("unsupported format %q; expected text or json", format)
+					}
+					repo, err := deps.GetRepoName()
+					if err != nil {
+						return fmt.Errorf("failed to detect repository: %w", err)
+					}
+					number, err := changeNumber(repo, c.Args().First(), strings.TrimSpace(c.String("branch")))
+					if err != nil {
+						return err
+					}
+
+					f := forge.Current()
+					report := func(s *github.PullRequestStatus) error {
+						if format != "text" {
+							return nil
+						}
+						_, err := fmt.Fprintln(c.App.Writer, s.Summary())
+						return err
+					}
+					status, err := pollStatus(c.Context, func() (*github.PullRequestStatus, error) {
+						return f.ChangeChecks(repo, number)
+					}, c.Bool("wait"), c.Duration("poll-interval"), c.Duration("timeout"), report)
+					if err != nil {
+						return err
+					}
+					if format == "json" {
+						enc := json.NewEncoder(c.App.Writer)
+						enc.SetIndent("", "  ")
+						if err := enc.Encode(status); err != nil {
+							return err
+						}
+					}
+					if status.ChecksState() == github.CheckFailure {
+						return cli.Exit(fmt.Sprintf("checks failed on PR #%d", status.Number), 1)
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// changeNumber parses the PR number argument, or finds the open PR from branch (default: the
+// current branch) into the repository's base branch
+func changeNumber(repo, arg, branch string) (int, error) {
+	if arg != "" {
+		number, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+		if err != nil || number <= 0 {
+			return 0, fmt.Errorf("invalid PR number %q", arg)
+		}
+		return number, nil
+	}
+	if branch == "" {
+		out, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+		if err != nil {
+			return 0, fmt.Errorf("failed to detect the current branch: %w", err)
+		}
+		branch = strings.TrimSpace(string(out))
+	}
+	base := registry.Current().BaseBranch(repo)
+	number, err := forge.Current().FindOpenChange(repo, branch, base)
+	if err != nil {
+		return 0, err
+	}
+	if number == 0 {
+		return 0, fmt.Errorf("no open PR from %s into %s; pass the PR number", branch, base)
+	}
+	return number, nil
+}
+
+// pollStatus fetches the status once, or until it settles when wait is set, calling report
+// whenever the summary changes. It fails when timeout expires before the status settles.
+func pollStatus(ctx context.Context, fetch func() (*github.PullRequestStatus, error), wait bool, interval, timeout time.Duration, report func(*github.PullRequestStatus) error) (*github.PullRequestStatus, error) {
+	deadline := time.Now().Add(timeout)
+	var last string
+	for {
+		status, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		if summary := status.Summary(); summary != last {
+			if err := report(status); err != nil {
+				return nil, err
+			}
+			last = summary
+		}
+		if !wait || status.Done() {
+			return status, nil
+		}
+		if !time.Now().Before(deadline) {
+			return status, fmt.Errorf("timed out after %s waiting for PR #%d: %s", timeout, status.Number, last)
+		}
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/github"
+)
+
+func TestPollStatus(t *testing.T) {
+	states := []string{github.CheckPending, github.CheckPending, github.CheckSuccess}
+	polls := 0
+	fetch := func() (*github.PullRequestStatus, error) {
+		s := &github.PullRequestStatus{
+			PullRequestState: github.PullRequestState{Number: 1, State: "open"},
+			Checks:           []github.Check{{Name: "test", State: states[polls]}},
+			Mergeable:        github.MergeableClean,
+		}
+		polls++
+		return s, nil
+	}
+	var reports int
+	report := func(*github.PullRequestStatus) error {
+		reports++
+		return nil
+	}
+
+	status, err := pollStatus(context.Background(), fetch, true, 0, time.Minute, report)
+	if err != nil || status.ChecksState() != github.CheckSuccess {
+		t.Fatalf("expected successful checks, got %+v (err %v)", status, err)
+	}
+	if polls != 3 || reports != 2 {
+		t.Fatalf("expected 3 polls and 2 changed summaries, got %d and %d", polls, reports)
+	}
+
+	polls = 0
+	if _, err := pollStatus(context.Background(), fetch, false, 0, time.Minute, report); err != nil || polls != 1 {
+		t.Fatalf("expected a single poll without --wait, got %d (err %v)", polls, err)
+	}
+	polls = 0
+	if _, err := pollStatus(context.Background(), fetch, true, 0, 0, report); err == nil {
+		t.Fatalf("expected a timeout")
+	}
+}
@@ -0,0 +1,248 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/bhanurp/jfrm/internal/config"
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/registry"
+	"github.com/bhanurp/jfrm/internal/version"
+	"github.com/urfave/cli/v2"
+)
+
+// Release creates the release command
+func Release() *cli.Command {
+	return &cli.Command{
+		Name:  "release",
+		Usage: "Create a draft release for the next version and publish it on approval",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "target",
+				Usage: "Branch or commit the release tag is created from (default: the repository's release branch)",
+			},
+			&cli.BoolFlag{
+				Name:  "approve",
+				Usage: "Publish the release without asking for confirmation",
+			},
+			changelogFlag(),
+		}, versionFlags()...),
+		Action: func(c *cli.Context) error {
+			cfg, err := loadConfig(c)
+			if err != nil {
+				return err
+			}
+			plan := versionPlan(c, version.BumpNone)
+			if err := plan.Validate(); err != nil {
+				return err
+			}
+
+			repo, err := deps.GetRepoName()
+			if err != nil {
+				return fmt.Errorf("failed to detect repository: %w", err)
+			}
+
+			discovery, err := releaseDiscovery(c, cfg, repo)
+			if err != nil {
+				return err
+			}
+
+			_, err = runRelease(bufio.NewReader(c.App.Reader), c.App.Writer, cfg, repo, releaseOptions{
+				Plan:      plan,
+				Discovery: discovery,
+				Target:    strings.TrimSpace(c.String("target")),
+				Changelog: strings.TrimSpace(c.String("changelog")),
+				Approve:   c.Bool("approve"),
+				DryRun:    c.Bool("dry-run"),
+			})
+			return err
+		},
+	}
+}
+
+// commitSHAPattern matches a full or abbreviated commit SHA given as the release target
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// releaseOptions controls a single release run
+type releaseOptions struct {
+	Plan      version.Plan
+	Discovery forge.Discovery
+	Target    string
+	Changelog string
+	Approve   bool
+	DryRun    bool
+	// SkipEmpty skips the release when no PRs were merged and no dependencies changed
+	SkipEmpty bool
+}
+
+// releaseResult describes the outcome of a release run
+type releaseResult struct {
+	Previous  string
+	Tag       string
+	Published bool
+	Skipped   bool
+}
+
+// runRelease computes the next version of repo and creates its draft release, publishing it
+// when approved. in is shared by every confirmation read from the same input. The working directory must be a clone of repo (used for the changelog and
+// dependency diff).
+func runRelease(in *bufio.Reader, out io.Writer, cfg *config.Config, repo string, opts releaseOptions) (releaseResult, error) {
+	var result releaseResult
+	rules, err := cfg.Release.Rules()
+	if err != nil {
+		return result, fmt.Errorf("invalid release rules: %w", err)
+	}
+
+	f := forge.Current()
+	tag, releasedTime, _, err := forge.Discover(f, repo, opts.Discovery)
+	if err != nil {
+		return result, fmt.Errorf("failed to get latest release: %w", err)
+	}
+	result.Previous = tag
+
+	prs, err := f.MergedChanges(repo, releasedTime)
+	if err != nil {
+		return result, fmt.Errorf("failed to fetch merged PRs: %w", err)
+	}
+
+	decision := rules.Evaluate(prs)
+	plan := opts.Plan
+	plan.Bump = decision.Bump
+	next, err := plan.Next(opts.Discovery.Version(tag))
+	if err != nil {
+		return result, fmt.Errorf("failed to compute next version: %w", err)
+	}
+	newTag := opts.Discovery.Tag(next)
+	result.Tag = newTag
+
+	releasable := releasablePRs(prs, decision)
+	notes := buildReleaseNotes(cfg, newTag, tag, releasable)
+	if opts.SkipEmpty && len(releasable) == 0 && len(notes.Dependencies) == 0 {
+		log.Printf("No changes in %s since %s — skipping release\n", repo, tag)
+		result.Tag, result.Skipped = tag, true
+		return result, nil
+	}
+	body, err := releaseNotesBody(cfg, notes)
+	if err != nil {
+		return result, err
+	}
+
+	target := opts.Target
+	if target == "" {
+		target = registry.Current().ReleaseBranch(repo)
+	}
+	if opts.Changelog != "" && commitSHAPattern.MatchString(target) {
+		return result, fmt.Errorf("--changelog needs a branch to commit to, but the target %s is a commit", target)
+	}
+
+	if opts.DryRun {
+		if opts.Changelog != "" {
+			log.Printf("[Dry Run] Would add %s to %s\n", next, opts.Changelog)
+		}
+		log.Printf("[Dry Run] Would create release %s (%s bump from %s)\n", newTag, decision.Bump, tag)
+		_, err := fmt.Fprint(out, body)
+		return result, err
+	}
+
+	if _, err := forgeToken("to create releases"); err != nil {
+		return result, err
+	}
+
+	rel, err := upsertDraftRelease(f, repo, github.Release{
+		TagName:         newTag,
+		TargetCommitish: target,
+		Name:            newTag,
+		Body:            body,
+		Draft:           true,
+		Prerelease:      version.IsPreRelease(next),
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if !opts.Approve && !confirm(in, out, fmt.Sprintf("Publish release %s of %s?", newTag, repo)) {
+		log.Printf("Release %s left as draft: %s\n", newTag, rel.HTMLURL)
+		return result, nil
+	}
+
+	// The changelog is only committed once the release is approved, and the tag points at that commit
+	if opts.Changelog != "" {
+		sha, err := commitChangelog(f, repo, opts.Changelog, target, tag, next, opts.Discovery, releasable, notes.Dependencies)
+		if err != nil {
+			return result, err
+		}
+		if sha != "" {
+			rel.TargetCommitish = sha
+		}
+	}
+	rel.Draft = false
+	if _, err := f.UpdateRelease(repo, *rel); err != nil {
+		return result, fmt.Errorf("failed to publish release: %w", err)
+	}
+	log.Printf("✅ Release %s published\n", newTag)
+	result.Published = true
+	return result, nil
+}
+
+// upsertDraftRelease creates the draft release, or refreshes an existing draft for the same tag
+func upsertDraftRelease(f forge.Forge, repo string, draft github.Release) (*github.Release, error) {
+	existing, err := f.FindRelease(repo, draft.TagName)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return f.CreateRelease(repo, draft)
+	}
+	if !existing.Draft {
+		return nil, fmt.Errorf("release %s is already published: %s", draft.TagName, existing.HTMLURL)
+	}
+	draft.ID = existing.ID
+	return f.UpdateRelease(repo, draft)
+}
+
+// commitChangelog adds the release to the changelog on the target branch and returns the
+// resulting commit SHA, or an empty SHA when the changelog already lists the version
+func commitChangelog(f forge.Forge, repo, path, branch, tag, next string, d forge.Discovery, prs []github.PullRequest, updates []deps.Update) (string, error) {
+	data, rev, err := f.GetFile(repo, path, branch)
+	if err != nil {
+		return "", err
+	}
+	out, changed, err := addChangelogRelease(data, repo, tag, next, d, changelogEntries(repo, prs, updates))
+	if err != nil || !changed {
+		return "", err
+	}
+	return f.PutFile(repo, path, branch, fmt.Sprintf("chore(release): update %s for %s", path, d.Tag(next)), out, rev)
+}
+
+// releasablePRs returns the PRs that were not excluded from release planning
+func releasablePRs(prs []github.PullRequest, decision version.Decision) []github.PullRequest {
+	skipped := make(map[int]bool)
+	for _, pr := range decision.Skipped {
+		skipped[pr.Number] = true
+	}
+	var out []github.PullRequest
+	for _, pr := range prs {
+		if !skipped[pr.Number] {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes. Callers asking several
+// questions pass the same reader, so that answers buffered from piped input are not lost.
+func confirm(in *bufio.Reader, out io.Writer, question string) bool {
+	_, _ = fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, err := in.ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
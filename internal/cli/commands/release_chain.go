@@ -0,0 +1,315 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/chain"
+	"github.com/bhanurp/jfrm/internal/config"
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/registry"
+	"github.com/urfave/cli/v2"
+)
+
+// ReleaseChain creates the release-chain command
+func ReleaseChain() *cli.Command {
+	return &cli.Command{
+		Name:  "release-chain",
+		Usage: "Release the JFrog repositories in dependency order, bumping each downstream repository",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "Start the chain at this repository (name or module path); only it and its dependents are released",
+			},
+			&cli.StringFlag{
+				Name:  "workspace",
+				Usage: "Directory holding the clones of the chain repositories",
+				Value: filepath.Join(".jfrm", "workspace"),
+			},
+			&cli.StringFlag{
+				Name:  "state",
+				Usage: "State file used to resume an interrupted chain",
+				Value: filepath.Join(".jfrm", "release-chain.json"),
+			},
+			&cli.BoolFlag{
+				Name:  "reset",
+				Usage: "Discard the saved state and start the chain from scratch",
+			},
+			&cli.BoolFlag{
+				Name:  "approve",
+				Usage: "Publish each release without asking for confirmation",
+			},
+			&cli.BoolFlag{
+				Name:  "wait",
+				Usage: "Poll until PRs are merged and releases reach the module proxy instead of stopping",
+			},
+			&cli.DurationFlag{
+				Name:  "poll-interval",
+				Usage: "Delay between polls when --wait is set",
+				Value: time.Minute,
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Maximum time to wait for a single PR merge or proxy release when --wait is set",
+				Value: 2 * time.Hour,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			cfg, err := loadConfig(c)
+			if err != nil {
+				return err
+			}
+
+			reg := registry.Current()
+			graph, err := reg.Dependencies(deps.GetLatestModFile)
+			if err != nil {
+				return fmt.Errorf("failed to derive the repository graph: %w", err)
+			}
+			repos := chain.FromRegistry(reg.Repositories(), graph)
+			if from := strings.TrimSpace(c.String("from")); from != "" {
+				if repos, err = chain.Downstream(repos, from); err != nil {
+					return err
+				}
+			}
+			ordered, err := chain.Order(repos)
+			if err != nil {
+				return err
+			}
+
+			statePath := c.String("state")
+			if c.Bool("reset") {
+				if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to reset state: %w", err)
+				}
+			}
+			state, err := chain.LoadState(statePath, ordered)
+			if err != nil {
+				return err
+			}
+
+			if c.Bool("dry-run") {
+				return printChainPlan(c.App.Writer, ordered, state)
+			}
+
+			if _, err := forgeToken("for the release chain"); err != nil {
+				return err
+			}
+
+			o := &chain.Orchestrator{
+				Repos: ordered,
+				State: state,
+				Runner: &chainRunner{
+					in:        bufio.NewReader(c.App.Reader),
+					out:       c.App.Writer,
+					cfg:       cfg,
+					workspace: c.String("workspace"),
+					approve:   c.Bool("approve"),
+				},
+				Wait:         c.Bool("wait"),
+				PollInterval: c.Duration("poll-interval"),
+				Timeout:      c.Duration("timeout"),
+			}
+			err = o.Run()
+			if errors.Is(err, chain.ErrWaiting) {
+				log.Printf("%v; rerun the command to resume", err)
+				return nil
+			}
+			return err
+		},
+	}
+}
+
+// printChainPlan lists the chain in release order with the saved progress
+func printChainPlan(w io.Writer, repos []chain.Repo, state *chain.State) error {
+	for i, r := range repos {
+		st := state.Get(r.Module)
+		line := fmt.Sprintf("%d. %s (%s): %s", i+1, r.Name(), r.Module, st.Step)
+		if st.PR != 0 {
+			line += fmt.Sprintf(", PR #%d", st.PR)
+		}
+		if st.Version != "" {
+			line += ", " + st.Version
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chainRunner performs the release chain steps on clones kept in the workspace
+type chainRunner struct {
+	in        *bufio.Reader
+	out       io.Writer
+	cfg       *config.Config
+	workspace string
+	// approve publishes releases without asking; otherwise each release is confirmed
+	approve bool
+}
+
+// Bump checks out a branch from the repository's base, requires the given versions and opens a PR
+func (r *chainRunner) Bump(repo chain.Repo, versions map[string]string) (string, int, error) {
+	if len(versions) == 0 {
+		return "", 0, nil
+	}
+	dir, base, err := r.checkoutBase(repo)
+	if err != nil {
+		return "", 0, err
+	}
+	required, err := requiredModules(dir)
+	if err != nil {
+		return "", 0, err
+	}
+
+	branch := buildBranchName("", "chain-"+time.Now().Format("20060102"))
+	if _, err := runIn(dir, "git", "checkout", "-B", branch); err != nil {
+		return "", 0, err
+	}
+
+	mods := make([]string, 0, len(versions))
+	for mod := range versions {
+		mods = append(mods, mod)
+	}
+	sort.Strings(mods)
+	var bumped []string
+	for _, mod := range mods {
+		if !required[mod] {
+			continue
+		}
+		if _, err := runIn(dir, "go", "get", mod+"@"+versions[mod]); err != nil {
+			return "", 0, err
+		}
+		bumped = append(bumped, fmt.Sprintf("%s %s", mod, versions[mod]))
+	}
+	if _, err := runIn(dir, "go", "mod", "tidy"); err != nil {
+		log.Printf("warning: failed running 'go mod tidy' in %s: %v", dir, err)
+	}
+	if status, err := runIn(dir, "git", "status", "--porcelain"); err != nil || status == "" {
+		return "", 0, err
+	}
+
+	message := fmt.Sprintf("chore: update dependencies\n\n%s", strings.Join(bumped, "\n"))
+	if _, err := runIn(dir, "git", "commit", "-am", message); err != nil {
+		return "", 0, err
+	}
+	if _, err := runIn(dir, "git", "push", "origin", branch, "--force-with-lease"); err != nil {
+		return "", 0, err
+	}
+	pr, err := forge.Current().CreateChange(repo.Slug, forge.Change{
+		Branch: branch,
+		Base:   base,
+		Title:  "Update dependencies",
+		Body:   "This PR updates Go dependencies to the latest versions.",
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return branch, pr, nil
+}
+
+// Merged reports whether the bump PR was merged
+func (r *chainRunner) Merged(repo chain.Repo, pr int) (bool, error) {
+	status, err := forge.Current().ChangeStatus(repo.Slug, pr)
+	if err != nil {
+		return false, err
+	}
+	return status.Merged, nil
+}
+
+// Release runs the release flow inside the repository's clone. A release left as draft
+// still counts as released, so the chain pauses until it is published and reaches the proxy.
+func (r *chainRunner) Release(repo chain.Repo) (string, bool, error) {
+	dir, _, err := r.checkoutBase(repo)
+	if err != nil {
+		return "", false, err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", false, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return "", false, err
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			log.Printf("Failed to restore working directory: %v", err)
+		}
+	}()
+
+	result, err := runRelease(r.in, r.out, r.cfg, repo.Slug, releaseOptions{
+		Discovery: r.cfg.Release.ReleaseDiscovery(registry.Current().BaseBranch(repo.Slug)),
+		Approve:   r.approve,
+		SkipEmpty: true,
+	})
+	if err != nil {
+		return "", false, err
+	}
+	if !result.Skipped && !result.Published {
+		log.Printf("Release chain paused at %s: publish the draft release %s, then rerun release-chain", repo.Name(), result.Tag)
+	}
+	return result.Tag, !result.Skipped, nil
+}
+
+// Available reports whether the released version is served by the module proxy
+func (r *chainRunner) Available(module, version string) (bool, error) {
+	return deps.IsVersionAvailable(module, version)
+}
+
+// checkoutBase clones the repository into the workspace if needed and checks out the latest base branch
+func (r *chainRunner) checkoutBase(repo chain.Repo) (dir, base string, err error) {
+	dir = filepath.Join(r.workspace, repo.Name())
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(r.workspace, 0755); err != nil {
+			return "", "", err
+		}
+		if _, err := runIn(r.workspace, "git", "clone", forge.Current().RepoURL(repo.Slug)+".git", repo.Name()); err != nil {
+			return "", "", err
+		}
+	}
+	_, base = resolveDefaultBase(repo.Slug)
+	if _, err := runIn(dir, "git", "fetch", "--tags", "origin", base); err != nil {
+		return "", "", err
+	}
+	if _, err := runIn(dir, "git", "checkout", "-B", base, "origin/"+base); err != nil {
+		return "", "", err
+	}
+	return dir, base, nil
+}
+
+// requiredModules returns the modules required by the go.mod in dir
+func requiredModules(dir string) (map[string]bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := deps.ParseDependencies(data)
+	if err != nil {
+		return nil, err
+	}
+	required := make(map[string]bool, len(parsed))
+	for mod := range parsed {
+		required[mod] = true
+	}
+	return required, nil
+}
+
+// runIn executes a command in dir and returns its trimmed combined output
+func runIn(dir, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s %s failed: %v: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
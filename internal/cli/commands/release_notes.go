@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/bhanurp/jfrm/internal/config"
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/releasenotes"
+	"github.com/urfave/cli/v2"
+)
+
+// ReleaseNotes creates the release-notes command
+func ReleaseNotes() *cli.Command {
+	return &cli.Command{
+		Name:    "release-notes",
+		Aliases: []string{"rn"},
+		Usage:   "Generate release notes for the changes since the latest release",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Write the notes to this file instead of stdout",
+			},
+			&cli.StringFlag{
+				Name:  "template",
+				Usage: "Path to a text/template file overriding the configured or built-in layout",
+			},
+		}, versionFlags()...),
+		Action: func(c *cli.Context) error {
+			cfg, err := loadConfig(c)
+			if err != nil {
+				return err
+			}
+			rules, err := cfg.Release.Rules()
+			if err != nil {
+				return fmt.Errorf("invalid release rules: %w", err)
+			}
+
+			repo, err := deps.GetRepoName()
+			if err != nil {
+				return fmt.Errorf("failed to detect repository: %w", err)
+			}
+
+			rel, discovery, err := latestRelease(c, repo)
+			if err != nil {
+				return err
+			}
+			tag, releasedTime := rel.Tag, rel.PublishedAt
+
+			prs, err := forge.Current().MergedChanges(repo, releasedTime)
+			if err != nil {
+				return fmt.Errorf("failed to fetch merged PRs: %w", err)
+			}
+
+			decision := rules.Evaluate(prs)
+			next, err := versionPlan(c, decision.Bump).Next(rel.Version)
+			if err != nil {
+				return fmt.Errorf("failed to compute next version: %w", err)
+			}
+
+			notes := buildReleaseNotes(cfg, discovery.Tag(next), tag, releasablePRs(prs, decision))
+
+			templatePath := c.String("template")
+			if templatePath == "" {
+				templatePath = cfg.ReleaseNotes.Template
+			}
+
+			outputFile := strings.TrimSpace(c.String("output"))
+			if outputFile == "" {
+				return notes.RenderFile(c.App.Writer, templatePath)
+			}
+			f, err := os.Create(outputFile)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", outputFile, err)
+			}
+			if err := notes.RenderFile(f, templatePath); err != nil {
+				_ = f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("failed to write %s: %w", outputFile, err)
+			}
+			log.Printf("✅ Release notes generated: %s", outputFile)
+			return nil
+		},
+	}
+}
+
+// buildReleaseNotes groups the PRs merged since previous and the managed dependency
+// updates since that tag into release notes for newTag
+func buildReleaseNotes(cfg *config.Config, newTag, previous string, prs []github.PullRequest) releasenotes.Notes {
+	var updates []deps.Update
+	before, err := deps.GetDependenciesAt(previous)
+	if err != nil {
+		log.Printf("Skipping dependency updates section: %v", err)
+	} else if after, err := deps.GetDependencies(); err != nil {
+		log.Printf("Skipping dependency updates section: %v", err)
+	} else {
+		updates = deps.DiffDependencies(before, after)
+	}
+	return releasenotes.Build(newTag, previous, prs, updates, cfg.ReleaseNotes.Options())
+}
+
+// releaseNotesBody renders the notes with the configured template
+func releaseNotesBody(cfg *config.Config, notes releasenotes.Notes) (string, error) {
+	var b strings.Builder
+	if err := notes.RenderFile(&b, cfg.ReleaseNotes.Template); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
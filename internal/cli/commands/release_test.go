@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/version"
+)
+
+func TestConfirm(t *testing.T) {
+	var out bytes.Buffer
+	if !confirm(bufio.NewReader(strings.NewReader("yes\n")), &out, "Publish?") {
+		t.Fatalf("expected yes to confirm")
+	}
+	if confirm(bufio.NewReader(strings.NewReader("\n")), &out, "Publish?") {
+		t.Fatalf("expected empty answer to decline")
+	}
+	if confirm(bufio.NewReader(strings.NewReader("")), &out, "Publish?") {
+		t.Fatalf("expected EOF to decline")
+	}
+
+	// Piped answers are read one per question from the shared reader
+	in := bufio.NewReader(strings.NewReader("y\nn\ny\n"))
+	for i, want := range []bool{true, false, true} {
+		if got := confirm(in, &out, "Publish?"); got != want {
+			t.Fatalf("answer %d: expected %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestReleasablePRs(t *testing.T) {
+	prs := []github.PullRequest{{Number: 1}, {Number: 2, Labels: []string{"ignore for release"}}}
+	got := releasablePRs(prs, version.DefaultRules().Evaluate(prs))
+	if len(got) != 1 || got[0].Number != 1 {
+		t.Fatalf("expected only PR #1, got %+v", got)
+	}
+}
+
+func TestAddChangelogReleaseUsesTagPrefix(t *testing.T) {
+	out, changed, err := addChangelogRelease(nil, "owner/repo", "api/v1.2.0", "1.3.0", forge.Discovery{Prefix: "api/v"}, map[string][]string{"Fixed": {"a fix"}})
+	if err != nil || !changed {
+		t.Fatalf("expected the release to be added (err %v)", err)
+	}
+	if !strings.Contains(string(out), "compare/api/v1.2.0...api/v1.3.0") {
+		t.Fatalf("expected compare links to use the tag prefix:\n%s", out)
+	}
+}
@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bhanurp/jfrm/internal/report"
+	"github.com/urfave/cli/v2"
+)
+
+// Report creates the report command comparing saved report snapshots
+func Report() *cli.Command {
+	return &cli.Command{
+		Name:  "report",
+		Usage: "Compare dependency report snapshots saved in the history directory",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "diff",
+				Usage:     "Show new updates, resolved updates and drift between two snapshots (default: the latest two)",
+				ArgsUsage: "[<from> <to>]",
+				Flags: []cli.Flag{
+					historyDirFlag(),
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "Output format: markdown or json",
+						Value:   "markdown",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					format := strings.ToLower(c.String("format"))
+					if format != "markdown" && format != "json" {
+						return fmt.Errorf("unsupported format %q; expected markdown or json", format)
+					}
+					dir, err := requiredHistoryDir(c)
+					if err != nil {
+						return err
+					}
+					from, to, err := diffSnapshots(dir, c.Args().Slice())
+					if err != nil {
+						return err
+					}
+					d := report.Compare(from, to)
+					if format == "json" {
+						enc := json.NewEncoder(c.App.Writer)
+						enc.SetIndent("", "  ")
+						return enc.Encode(d)
+					}
+					_, err = io.WriteString(c.App.Writer, d.Markdown())
+					return err
+				},
+			},
+			{
+				Name:  "trend",
+				Usage: "Render the saved snapshots as a Markdown table",
+				Flags: []cli.Flag{
+					historyDirFlag(),
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "Only include the latest N snapshots (0 includes all)",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Write the table to this file instead of stdout",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					dir, err := requiredHistoryDir(c)
+					if err != nil {
+						return err
+					}
+					history, err := report.LoadHistory(dir)
+					if err != nil {
+						return err
+					}
+					if len(history) == 0 {
+						return fmt.Errorf("no snapshots found in %s", dir)
+					}
+					if limit := c.Int("limit"); limit > 0 && len(history) > limit {
+						history = history[len(history)-limit:]
+					}
+					table := report.Trend(history)
+					if output := c.String("output"); output != "" {
+						return os.WriteFile(output, []byte(table), 0644)
+					}
+					_, err = io.WriteString(c.App.Writer, table)
+					return err
+				},
+			},
+		},
+	}
+}
+
+// historyDirFlag selects the directory report snapshots are saved in
+func historyDirFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "history-dir",
+		Usage: "Directory keeping a JSON snapshot of every report (default: report.historyDir from the configuration)",
+	}
+}
+
+// reportHistoryDir returns the history directory from --history-dir or the configuration
+func reportHistoryDir(c *cli.Context) (string, error) {
+	if dir := c.String("history-dir"); dir != "" {
+		return dir, nil
+	}
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return "", err
+	}
+	return cfg.Report.HistoryDir, nil
+}
+
+// requiredHistoryDir is reportHistoryDir for commands that cannot run without snapshots
+func requiredHistoryDir(c *cli.Context) (string, error) {
+	dir, err := reportHistoryDir(c)
+	if err == nil && dir == "" {
+		err = fmt.Errorf("no history directory; set --history-dir or report.historyDir in the configuration")
+	}
+	return dir, err
+}
+
+// saveReportSnapshot stores r in the history directory when one is configured
+func saveReportSnapshot(c *cli.Context, r *report.Report) error {
+	dir, err := reportHistoryDir(c)
+	if err != nil || dir == "" {
+		return err
+	}
+	path, err := report.SaveSnapshot(dir, r)
+	if err != nil {
+		return fmt.Errorf("failed to save report snapshot: %w", err)
+	}
+	log.Printf("Saved report snapshot: %s", path)
+	return nil
+}
+
+// diffSnapshots loads the snapshots named in args (paths or names within dir), defaulting to the latest two
+func diffSnapshots(dir string, args []string) (report.Snapshot, report.Snapshot, error) {
+	switch len(args) {
+	case 0:
+		history, err := report.LoadHistory(dir)
+		if err != nil {
+			return report.Snapshot{}, report.Snapshot{}, err
+		}
+		if len(history) < 2 {
+			return report.Snapshot{}, report.Snapshot{}, fmt.Errorf("need at least two snapshots in %s, found %d", dir, len(history))
+		}
+		return history[len(history)-2], history[len(history)-1], nil
+	case 2:
+		var snapshots [2]report.Snapshot
+		for i, arg := range args {
+			path := arg
+			if _, err := os.Stat(path); err != nil && dir != "" {
+				path = filepath.Join(dir, arg)
+			}
+			s, err := report.LoadSnapshot(path)
+			if err != nil {
+				return report.Snapshot{}, report.Snapshot{}, err
+			}
+			snapshots[i] = s
+		}
+		return snapshots[0], snapshots[1], nil
+	}
+	return report.Snapshot{}, report.Snapshot{}, fmt.Errorf("expected no arguments or <from> <to>")
+}
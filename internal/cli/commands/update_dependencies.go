@@ -1,16 +1,19 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/forge"
 	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/httpclient"
+	"github.com/bhanurp/jfrm/internal/registry"
 	"github.com/bhanurp/jfrm/internal/report"
 	"github.com/bhanurp/jfrm/internal/version"
 	"github.com/urfave/cli/v2"
@@ -22,7 +25,7 @@ func UpdateDependencies() *cli.Command {
 		Name:    "update-dependencies",
 		Aliases: []string{"ud"},
 		Usage:   "Update Go dependencies to latest versions",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.BoolFlag{
 				Name:    "dry-run",
 				Aliases: []string{"d"},
@@ -35,64 +38,58 @@ func UpdateDependencies() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:  "remote",
-				Usage: "Base in form <remote>/<branch> (default: upstream/dev; upstream/main for jfrog/jfrog-cli-artifactory)",
+				Usage: "Base in form <remote>/<branch> (default: upstream/<base branch of the repository>, e.g. upstream/dev)",
 			},
 			&cli.StringFlag{
 				Name:  "new-branch",
 				Usage: "Override the generated branch name (e.g., update-dependencies-1.2.3)",
 			},
-		},
+			changelogFlag(),
+			reportFormatFlag(),
+			templateDirFlag(),
+			historyDirFlag(),
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output file path for the dry-run report (default: dry-run-report with the extension of the format)",
+			},
+		}, versionFlags()...),
 		Action: func(c *cli.Context) error {
 			dryRun := c.Bool("dry-run")
 			createPR := c.Bool("create-pr")
+			reportFormat := c.String("format")
+			if _, err := report.Extension(reportFormat); err != nil {
+				return err
+			}
+			templateDir, err := reportTemplateDir(c)
+			if err != nil {
+				return err
+			}
 
-			// Determine default base remote/branch
 			repo, err := deps.GetRepoName()
 			if err != nil {
 				return fmt.Errorf("failed to detect repository: %w", err)
 			}
-			baseRemote, baseBranch := resolveDefaultBase(repo)
-
-			userBase := strings.TrimSpace(c.String("remote"))
-			if userBase != "" {
-				parts := strings.SplitN(userBase, "/", 2)
-				if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-					return fmt.Errorf("invalid --remote value; expected <remote>/<branch>")
-				}
-				baseRemote, baseBranch = parts[0], parts[1]
-			}
 
 			// Preflight validation before any changes
 			if err := runPreflightChecks(createPR); err != nil {
 				return err
 			}
 
-			// Validate remote exists; if missing and user did not specify --remote, fallback to origin
-			remotesOut, err := exec.Command("git", "remote").CombinedOutput()
+			// Determine base remote/branch
+			baseRemote, baseBranch, err := resolveBaseRef(repo, strings.TrimSpace(c.String("remote")))
 			if err != nil {
-				return fmt.Errorf("failed to list remotes: %w", err)
+				return err
 			}
-			remotes := string(remotesOut)
-			if !strings.Contains(remotes, baseRemote) {
-				if userBase != "" {
-					return fmt.Errorf("remote '%s' not found; configure it first", baseRemote)
-				}
-				// fallback to origin
-				baseRemote = "origin"
-				if !strings.Contains(remotes, baseRemote) {
-					return fmt.Errorf("remote '%s' not found; configure it first", baseRemote)
-				}
-				if detected := detectDefaultRemoteBranch(baseRemote); detected != "" {
-					if parts := strings.SplitN(detected, "/", 2); len(parts) == 2 {
-						baseBranch = parts[1]
-					}
-				}
+
+			rules, err := loadReleaseRules(c)
+			if err != nil {
+				return fmt.Errorf("invalid release rules: %w", err)
 			}
 
-			// Fetch the base branch refs (best-effort) and verify
-			_ = exec.Command("git", "fetch", baseRemote, baseBranch).Run()
-			if err := exec.Command("git", "rev-parse", "--verify", fmt.Sprintf("refs/remotes/%s/%s", baseRemote, baseBranch)).Run(); err != nil {
-				return fmt.Errorf("base '%s/%s' not found after fetch", baseRemote, baseBranch)
+			plan := versionPlan(c, version.BumpNone)
+			if err := plan.Validate(); err != nil {
+				return err
 			}
 
 			if dryRun {
@@ -108,34 +105,30 @@ func UpdateDependencies() *cli.Command {
 			}
 
 			// Update dependencies
+			statuses := resolveStatuses(c, dependencies)
 			updates := make(map[string]string)
-			for mod, currentVer := range dependencies {
-				if !deps.IsAllowedDependency(mod) {
-					continue
-				}
-				fmt.Printf("Fetching latest version for: %s\n", mod)
-				latestVer, err := deps.GetLatestModuleVersion(mod)
-				if err != nil {
-					log.Printf("Skipping %s: %v", mod, err)
-					continue
-				}
-				if deps.IsNewerVersion(currentVer, latestVer) {
-					log.Printf("Updating %s from %s -> %s", mod, currentVer, latestVer)
-					updates[mod] = latestVer
-				}
-				if err := deps.UpdateDependency(mod, currentVer, latestVer, dryRun); err != nil {
-					log.Printf("Failed to update %s: %v", mod, err)
+			for _, s := range statuses {
+				switch s.Status {
+				case deps.StatusError:
+					log.Printf("Skipping %s: %s", s.Module, s.Error)
+				case deps.StatusOutdated:
+					log.Printf("Updating %s from %s -> %s", s.Module, s.Current, s.Latest)
+					updates[s.Module] = s.Latest
+					if err := deps.UpdateDependency(s.Module, s.Current, s.Latest, dryRun); err != nil {
+						log.Printf("Failed to update %s: %v", s.Module, err)
+					}
 				}
 			}
 
 			// Get latest release information and merged PRs (for next version prediction)
-			tag, lastReleaseSHA, releasedTime, err := github.GetLatestReleaseVersionAndCommitSHA(repo)
+			rel, discovery, err := latestRelease(c, repo)
 			if err != nil {
-				return fmt.Errorf("failed to get latest release: %w", err)
+				return err
 			}
-			log.Printf("Latest release: %s (Commit: %s) released on [%s]\n", tag, lastReleaseSHA, releasedTime.GoString())
+			tag, releasedTime := rel.Tag, rel.PublishedAt
+			log.Printf("Latest release: %s\n", rel)
 
-			prs, err := github.GetAllMergedPRs(repo, releasedTime)
+			prs, err := forge.Current().MergedChanges(repo, releasedTime)
 			if err != nil {
 				log.Printf("Error fetching merged PRs: %v\n", err)
 			}
@@ -155,9 +148,29 @@ func UpdateDependencies() *cli.Command {
 				return nil
 			}
 
+			decision := rules.Evaluate(prs)
+			plan.Bump = decision.Bump
+			changelogFile := strings.TrimSpace(c.String("changelog"))
+			nextVersion, err := plan.Next(rel.Version)
+			if err != nil {
+				// A PR branch and title or a changelog heading needs a real version; the
+				// placeholder is only used for logs and reports
+				if createPR || changelogFile != "" {
+					return fmt.Errorf("failed to compute next version: %w", err)
+				}
+				log.Printf("warning: failed to compute next version: %v", err)
+				nextVersion = "next"
+			}
+
 			// Generate report if in dry-run mode
 			if dryRun {
-				return report.GenerateDryRunReport(repo, prs, tag)
+				r := report.New(report.KindDryRun, repo, statuses, prs, tag, nextVersion, decision)
+				r.LatestReleaseDate = releasedTime
+				r.Stale = r.Stale || c.Bool("offline")
+				if err := saveReportSnapshot(c, r); err != nil {
+					return err
+				}
+				return report.GenerateDryRunReport(r, reportFormat, c.String("output"), templateDir)
 			}
 
 			// Ensure go.sum is updated after any changes
@@ -167,20 +180,23 @@ func UpdateDependencies() *cli.Command {
 				}
 			}
 
+			changedFiles := []string{"go.mod", "go.sum"}
+			if changelogFile != "" {
+				if err := updateLocalChangelog(changelogFile, repo, tag, nextVersion, discovery, changelogEntries(repo, releasablePRs(prs, decision), dependencyUpdates(dependencies, updates))); err != nil {
+					return err
+				}
+				changedFiles = append(changedFiles, changelogFile)
+			}
+
 			// Create PR if requested
 			if createPR {
-				releaseType := version.DetermineReleaseType(prs)
-				nextVersion := version.GetNextVersion(tag, releaseType)
-				if strings.TrimSpace(nextVersion) == "" {
-					nextVersion = "next"
-				}
 				branchName := buildBranchName(c.String("new-branch"), nextVersion)
 
 				// Create local branch from the remote base
 				if err := exec.Command("git", "checkout", "-B", branchName, fmt.Sprintf("%s/%s", baseRemote, baseBranch)).Run(); err != nil {
 					return fmt.Errorf("failed to create branch from %s/%s: %w", baseRemote, baseBranch, err)
 				}
-				if err := deps.GitExec("add", "go.mod", "go.sum"); err != nil {
+				if err := deps.GitExec(append([]string{"add"}, changedFiles...)...); err != nil {
 					return fmt.Errorf("failed to add files: %w", err)
 				}
 				if err := deps.GitExec("commit", "-m", fmt.Sprintf("chore(%s): update dependencies to latest versions", nextVersion)); err != nil {
@@ -190,13 +206,23 @@ func UpdateDependencies() *cli.Command {
 					return fmt.Errorf("failed to push: %w", err)
 				}
 
-				token := os.Getenv("GITHUB_TOKEN")
-				prID, err := github.CreatePullRequest(branchName, baseBranch, repo, token)
+				if _, err := forgeToken("for PR creation"); err != nil {
+					return err
+				}
+				f := forge.Current()
+				prID, err := f.CreateChange(repo, forge.Change{
+					Branch: branchName,
+					Base:   baseBranch,
+					Title:  "Update dependencies",
+					Body:   "This PR updates Go dependencies to the latest versions.",
+				})
 				if err != nil {
 					return fmt.Errorf("failed to create PR: %w", err)
 				}
-				if err := github.GetPullRequestStatus(prID, repo, token); err != nil {
+				if status, err := f.ChangeStatus(repo, prID); err != nil {
 					log.Printf("Failed to get PR status: %v", err)
+				} else {
+					log.Printf("PR Status: %s\n", status.State)
 				}
 			}
 
@@ -205,13 +231,60 @@ func UpdateDependencies() *cli.Command {
 	}
 }
 
+// dependencyUpdates converts the applied updates into a list sorted by module
+func dependencyUpdates(current, updates map[string]string) []deps.Update {
+	before := make(map[string]string, len(updates))
+	for mod := range updates {
+		before[mod] = current[mod]
+	}
+	return deps.DiffDependencies(before, updates)
+}
+
+// resolveBaseRef determines the <remote>/<branch> changes are based on, honouring a
+// user-provided --remote value, and fetches it
+func resolveBaseRef(repo, userBase string) (baseRemote, baseBranch string, err error) {
+	baseRemote, baseBranch = resolveDefaultBase(repo)
+	if userBase != "" {
+		parts := strings.SplitN(userBase, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return "", "", fmt.Errorf("invalid --remote value; expected <remote>/<branch>")
+		}
+		baseRemote, baseBranch = parts[0], parts[1]
+	}
+
+	// Validate remote exists; if missing and user did not specify --remote, fallback to origin
+	remotesOut, err := exec.Command("git", "remote").CombinedOutput()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list remotes: %w", err)
+	}
+	remotes := string(remotesOut)
+	if !strings.Contains(remotes, baseRemote) {
+		if userBase != "" {
+			return "", "", fmt.Errorf("remote '%s' not found; configure it first", baseRemote)
+		}
+		// fallback to origin
+		baseRemote = "origin"
+		if !strings.Contains(remotes, baseRemote) {
+			return "", "", fmt.Errorf("remote '%s' not found; configure it first", baseRemote)
+		}
+		if detected := detectDefaultRemoteBranch(baseRemote); detected != "" {
+			if parts := strings.SplitN(detected, "/", 2); len(parts) == 2 {
+				baseBranch = parts[1]
+			}
+		}
+	}
+
+	// Fetch the base branch refs (best-effort) and verify
+	_ = exec.Command("git", "fetch", baseRemote, baseBranch).Run()
+	if err := exec.Command("git", "rev-parse", "--verify", fmt.Sprintf("refs/remotes/%s/%s", baseRemote, baseBranch)).Run(); err != nil {
+		return "", "", fmt.Errorf("base '%s/%s' not found after fetch", baseRemote, baseBranch)
+	}
+	return baseRemote, baseBranch, nil
+}
+
 // Helpers kept unexported for testing
 func resolveDefaultBase(repo string) (remote, branch string) {
-	remote, branch = "upstream", "dev"
-	if repo == "jfrog/jfrog-cli-artifactory" {
-		branch = "main"
-	}
-	return
+	return "upstream", registry.Current().BaseBranch(repo)
 }
 
 func buildBranchName(override, next string) string {
@@ -280,16 +353,19 @@ func runPreflightChecks(requirePR bool) error {
 
 	// If PR creation requested, validate token and remote push access (best-effort)
 	if requirePR {
-		if os.Getenv("GITHUB_TOKEN") == "" {
-			issues = append(issues, "GITHUB_TOKEN is not set (required for PR creation)")
+		if _, err := forgeToken("for PR creation"); err != nil {
+			issues = append(issues, err.Error())
 		}
 		// Quick GitHub API reachability check
-		client := &http.Client{Timeout: 5 * time.Second}
-		resp, err := client.Get("https://api.github.com/rate_limit")
-		if err != nil || resp.StatusCode >= 400 {
-			issues = append(issues, "cannot reach GitHub API (network/auth issue)")
-		} else {
-			_ = resp.Body.Close()
+		if forge.Current().Kind() == forge.KindGitHub {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			resp, err := httpclient.Default().Get(ctx, github.CurrentHost().APIURL+"/rate_limit")
+			cancel()
+			if err != nil || resp.StatusCode >= 400 {
+				issues = append(issues, "cannot reach GitHub API (network/auth issue)")
+			} else {
+				_ = resp.Body.Close()
+			}
 		}
 	}
 
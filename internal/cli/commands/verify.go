@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/policy"
+	"github.com/bhanurp/jfrm/internal/registry"
+	"github.com/bhanurp/jfrm/internal/resolver"
+	"github.com/urfave/cli/v2"
+)
+
+// verifyResult is the machine-readable output of the verify command
+type verifyResult struct {
+	Branch        string            `json:"branch"`
+	ReleaseBranch bool              `json:"releaseBranch"`
+	Stale         bool              `json:"stale"`
+	Violations    []verifyViolation `json:"violations"`
+}
+
+// verifyViolation is a policy violation with its severity spelled out
+type verifyViolation struct {
+	policy.Violation
+	Severity string `json:"severity"`
+}
+
+// Verify creates the verify command
+func Verify() *cli.Command {
+	return &cli.Command{
+		Name:  "verify",
+		Usage: "Check the managed dependencies against the configured policy and fail CI on violations",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "branch",
+				Usage:   "Branch the dependencies are verified for (default: the current git branch)",
+				EnvVars: []string{"GITHUB_BASE_REF"},
+			},
+			&cli.IntFlag{
+				Name:  "max-minor-lag",
+				Usage: "Override the number of minor versions a dependency may trail its latest release",
+			},
+			&cli.IntFlag{
+				Name:  "max-days-behind",
+				Usage: "Override how many days a newer version may have been available",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Output format: text or json",
+				Value:   "text",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			format := strings.ToLower(c.String("format"))
+			if format != "text" && format != "json" {
+				return fmt.Errorf("unsupported format %q; expected text or json", format)
+			}
+
+			cfg, err := loadConfig(c)
+			if err != nil {
+				return err
+			}
+			p, err := cfg.Verify.Policy()
+			if err != nil {
+				return fmt.Errorf("invalid verify policy: %w", err)
+			}
+			if c.IsSet("max-minor-lag") {
+				p.MaxMinorLag = c.Int("max-minor-lag")
+			}
+			if c.IsSet("max-days-behind") {
+				p.MaxDaysBehind = c.Int("max-days-behind")
+			}
+			if repo, err := deps.GetRepoName(); err == nil {
+				if release := registry.Current().ReleaseBranch(repo); release != registry.Current().BaseBranch(repo) {
+					p.ReleaseBranches = append(p.ReleaseBranches, release)
+				}
+			}
+
+			branch := strings.TrimSpace(c.String("branch"))
+			if branch == "" {
+				out, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+				if err != nil {
+					log.Printf("Failed to detect the current branch: %v", err)
+				}
+				branch = strings.TrimSpace(string(out))
+			}
+
+			dependencies, err := deps.GetDependencies()
+			if err != nil {
+				return fmt.Errorf("failed to read go.mod: %w", err)
+			}
+
+			resolver.Shared().Resolve(c.Context, managedModules(dependencies))
+			result := verifyResult{Branch: branch, ReleaseBranch: p.IsReleaseBranch(branch), Stale: c.Bool("offline"), Violations: []verifyViolation{}}
+			violations := p.Evaluate(dependencies, result.ReleaseBranch, policy.ProxySource{}, time.Now())
+			for _, v := range violations {
+				result.Violations = append(result.Violations, verifyViolation{Violation: v, Severity: v.Severity.String()})
+			}
+			if err := writeVerify(c.App.Writer, result, format); err != nil {
+				return err
+			}
+			if worst := policy.Worst(violations); worst != policy.SeverityNone {
+				return cli.Exit(fmt.Sprintf("%d policy violation(s), worst severity %s", len(violations), worst), worst.ExitCode())
+			}
+			return nil
+		},
+	}
+}
+
+// writeVerify renders the verification result in the requested format
+func writeVerify(w io.Writer, r verifyResult, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	if r.Stale {
+		fmt.Fprintln(w, "⚠️  Offline: versions and advisories come from local caches and may be stale")
+	}
+	if len(r.Violations) == 0 {
+		_, err := fmt.Fprintf(w, "✅ No policy violations on %s\n", r.Branch)
+		return err
+	}
+	for _, v := range r.Violations {
+		icon := "⚠️ "
+		if v.Violation.Severity == policy.SeverityError {
+			icon = "❌"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s %s@%s [%s]: %s\n", icon, strings.ToUpper(v.Severity), v.Module, v.Version, v.Rule, v.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
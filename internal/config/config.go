@@ -0,0 +1,271 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/bhanurp/jfrm/internal/auth"
+	"github.com/bhanurp/jfrm/internal/forge"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/policy"
+	"github.com/bhanurp/jfrm/internal/registry"
+	"github.com/bhanurp/jfrm/internal/releasenotes"
+	"github.com/bhanurp/jfrm/internal/version"
+)
+
+// DefaultFile is the configuration file looked up in the project root
+const DefaultFile = ".jfrm.json"
+
+// Config holds the user configuration for jfrm
+type Config struct {
+	Release      Release      `json:"release"`
+	ReleaseNotes ReleaseNotes `json:"releaseNotes"`
+	Verify       Verify       `json:"verify"`
+	Report       Report       `json:"report"`
+	GitHub       GitHub       `json:"github"`
+	Forge        Forge        `json:"forge"`
+	// Repositories extends or overrides the built-in repository registry
+	Repositories []registry.Repository `json:"repositories,omitempty"`
+}
+
+// Release configures how the next release type is determined
+type Release struct {
+	// Labels maps a PR label to "major", "minor", "patch" or "none"
+	Labels map[string]string `json:"labels,omitempty"`
+	// SkipLabels excludes PRs carrying any of these labels from release planning
+	SkipLabels []string `json:"skipLabels,omitempty"`
+	// ConventionalCommits toggles Conventional Commit title and footer parsing (default true)
+	ConventionalCommits *bool `json:"conventionalCommits,omitempty"`
+	// CommitTypes maps a Conventional Commit type such as "feat" to a bump
+	CommitTypes map[string]string `json:"commitTypes,omitempty"`
+	// DefaultBump is applied to PRs that match no rule (default "patch")
+	DefaultBump string `json:"defaultBump,omitempty"`
+	// Discovery selects where the latest release is found: "release" (default), "tag" or "git"
+	Discovery string `json:"discovery,omitempty"`
+	// TagPrefix is the part of release tags before the version (default "v")
+	TagPrefix string `json:"tagPrefix,omitempty"`
+	// PreReleases lets a pre-release be discovered as the latest release
+	PreReleases bool `json:"preReleases,omitempty"`
+}
+
+// ReleaseNotes configures the generated release notes
+type ReleaseNotes struct {
+	// Categories replaces the default sections when set
+	Categories []releasenotes.Category `json:"categories,omitempty"`
+	// ExcludeLabels replaces the default excluded labels when set
+	ExcludeLabels []string `json:"excludeLabels,omitempty"`
+	// ExcludeAuthors drops PRs by these authors, e.g. "*[bot]"
+	ExcludeAuthors []string `json:"excludeAuthors,omitempty"`
+	// Template is the path of a text/template file used instead of the built-in layout
+	Template string `json:"template,omitempty"`
+}
+
+// Verify configures the rules enforced by the verify command
+type Verify struct {
+	// MaxMinorLag is the number of minor versions a dependency may trail its latest release
+	MaxMinorLag *int `json:"maxMinorLag,omitempty"`
+	// MaxDaysBehind is how many days the first newer version may have been available
+	MaxDaysBehind *int `json:"maxDaysBehind,omitempty"`
+	// DisallowPseudoVersions rejects pseudo-versions on release branches (default true)
+	DisallowPseudoVersions *bool `json:"disallowPseudoVersions,omitempty"`
+	// ReleaseBranches replaces the default release branch patterns ("release/*") when set
+	ReleaseBranches []string `json:"releaseBranches,omitempty"`
+	// Retracted rejects retracted versions (default true)
+	Retracted *bool `json:"retracted,omitempty"`
+	// Vulnerabilities rejects versions with known vulnerabilities (default true)
+	Vulnerabilities *bool `json:"vulnerabilities,omitempty"`
+	// Severities maps a rule name to "off", "warning" or "error"
+	Severities map[string]string `json:"severities,omitempty"`
+}
+
+// Report configures the generated dependency and dry-run reports
+type Report struct {
+	// TemplateDir holds templates overriding the built-in layouts, e.g. dependency.md.tmpl
+	TemplateDir string `json:"templateDir,omitempty"`
+	// HistoryDir keeps a JSON snapshot of every generated report when set
+	HistoryDir string `json:"historyDir,omitempty"`
+}
+
+// GitHub configures the GitHub host jfrm talks to and how it authenticates
+type GitHub struct {
+	// Host is the GitHub host, e.g. a GitHub Enterprise Server (default: the host of the git remote)
+	Host string `json:"host,omitempty"`
+	// APIURL, UploadURL and WebURL override the endpoints derived from Host
+	APIURL    string `json:"apiUrl,omitempty"`
+	UploadURL string `json:"uploadUrl,omitempty"`
+	WebURL    string `json:"webUrl,omitempty"`
+	// Token is used when neither GITHUB_TOKEN nor GH_TOKEN is set
+	Token string `json:"token,omitempty"`
+	// Tokens maps a host name to its token, taking precedence over Token
+	Tokens map[string]string `json:"tokens,omitempty"`
+	// App authenticates as a GitHub App installation instead of a personal token
+	App *GitHubApp `json:"app,omitempty"`
+}
+
+// Forge selects the code hosting service of the repository
+type Forge struct {
+	// Type is "github", "gitlab" or "gitea" (default: detected from the remote host)
+	Type string `json:"type,omitempty"`
+	// Host is the GitLab or Gitea host (default: the host of the git remote)
+	Host string `json:"host,omitempty"`
+	// APIURL overrides the API root, https://<host>/api/v4 on GitLab and https://<host>/api/v1 on Gitea
+	APIURL string `json:"apiUrl,omitempty"`
+	// Token authenticates to GitLab or Gitea when GITLAB_TOKEN or GITEA_TOKEN is not set
+	Token string `json:"token,omitempty"`
+}
+
+// GitHubApp identifies a GitHub App and its private key
+type GitHubApp struct {
+	ID int64 `json:"id"`
+	// InstallationID is looked up from the repository when omitted
+	InstallationID int64 `json:"installationId,omitempty"`
+	// PrivateKeyFile is the path of the PEM private key downloaded from the app settings
+	PrivateKeyFile string `json:"privateKeyFile"`
+}
+
+// Load reads the configuration from path. A missing file yields an empty configuration
+// when path is the default location; an explicitly given file must exist.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+	if strings.TrimSpace(path) == "" {
+		path = DefaultFile
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && path == DefaultFile {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Rules builds the release rules, layering configured values over the defaults
+func (r Release) Rules() (version.Rules, error) {
+	rules := version.DefaultRules()
+	for label, name := range r.Labels {
+		b, err := version.ParseBump(name)
+		if err != nil {
+			return rules, fmt.Errorf("label %q: %w", label, err)
+		}
+		rules.Labels[strings.ToLower(label)] = b
+	}
+	if len(r.SkipLabels) > 0 {
+		rules.SkipLabels = r.SkipLabels
+	}
+	if r.ConventionalCommits != nil {
+		rules.ConventionalCommits = *r.ConventionalCommits
+	}
+	for commitType, name := range r.CommitTypes {
+		b, err := version.ParseBump(name)
+		if err != nil {
+			return rules, fmt.Errorf("commit type %q: %w", commitType, err)
+		}
+		rules.CommitTypes[strings.ToLower(commitType)] = b
+	}
+	if r.DefaultBump != "" {
+		b, err := version.ParseBump(r.DefaultBump)
+		if err != nil {
+			return rules, fmt.Errorf("defaultBump: %w", err)
+		}
+		rules.Default = b
+	}
+	return rules, nil
+}
+
+// ReleaseDiscovery builds the release discovery settings for a repository whose base branch is branch
+func (r Release) ReleaseDiscovery(branch string) forge.Discovery {
+	return forge.Discovery{Mode: r.Discovery, Prefix: r.TagPrefix, PreReleases: r.PreReleases, Branch: branch}
+}
+
+// Options builds the release notes options, layering configured values over the defaults
+func (r ReleaseNotes) Options() releasenotes.Options {
+	opts := releasenotes.DefaultOptions()
+	if len(r.Categories) > 0 {
+		opts.Categories = r.Categories
+	}
+	if len(r.ExcludeLabels) > 0 {
+		opts.ExcludeLabels = r.ExcludeLabels
+	}
+	opts.ExcludeAuthors = r.ExcludeAuthors
+	return opts
+}
+
+// Policy builds the verification policy, layering configured values over the defaults
+func (v Verify) Policy() (policy.Policy, error) {
+	p := policy.DefaultPolicy()
+	if v.MaxMinorLag != nil {
+		p.MaxMinorLag = *v.MaxMinorLag
+	}
+	if v.MaxDaysBehind != nil {
+		p.MaxDaysBehind = *v.MaxDaysBehind
+	}
+	if v.DisallowPseudoVersions != nil {
+		p.DisallowPseudoVersions = *v.DisallowPseudoVersions
+	}
+	if len(v.ReleaseBranches) > 0 {
+		p.ReleaseBranches = v.ReleaseBranches
+	}
+	if v.Retracted != nil {
+		p.CheckRetracted = *v.Retracted
+	}
+	if v.Vulnerabilities != nil {
+		p.CheckVulnerabilities = *v.Vulnerabilities
+	}
+	for name, severity := range v.Severities {
+		rule := policy.Rule(strings.ToLower(name))
+		if _, ok := p.Severities[rule]; !ok {
+			return p, fmt.Errorf("unknown rule %q", name)
+		}
+		s, err := policy.ParseSeverity(severity)
+		if err != nil {
+			return p, fmt.Errorf("rule %q: %w", name, err)
+		}
+		p.Severities[rule] = s
+	}
+	return p, nil
+}
+
+// Endpoints returns the GitHub host to use: the configured host, or remoteHost when none is
+// configured, with the configured endpoint overrides applied
+func (g GitHub) Endpoints(remoteHost string) github.Host {
+	name := g.Host
+	if name == "" {
+		name = remoteHost
+	}
+	return github.HostFor(name).With(github.Host{APIURL: g.APIURL, UploadURL: g.UploadURL, WebURL: g.WebURL})
+}
+
+// Auth builds the GitHub token provider for host; repo is the slug used to find a GitHub App
+// installation and may be empty
+func (g GitHub) Auth(host github.Host, repo string) (*auth.Provider, error) {
+	p := &auth.Provider{Token: g.Token, Tokens: g.Tokens}
+	if g.App == nil {
+		return p, nil
+	}
+	if g.App.ID == 0 || g.App.PrivateKeyFile == "" {
+		return nil, fmt.Errorf("github.app needs both id and privateKeyFile")
+	}
+	key, err := auth.LoadPrivateKey(g.App.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	p.App = &auth.App{ID: g.App.ID, PrivateKey: key, InstallationID: g.App.InstallationID, Repo: repo, Host: host.Name, APIBase: host.APIURL}
+	return p, nil
+}
+
+// Registry returns the built-in repository registry extended with the configured repositories
+func (c *Config) Registry() (*registry.Registry, error) {
+	reg := registry.Default().With(c.Repositories)
+	if err := reg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid repositories: %w", err)
+	}
+	return reg, nil
+}
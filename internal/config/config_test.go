@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bhanurp/jfrm/internal/policy"
+	"github.com/bhanurp/jfrm/internal/version"
+)
+
+func TestLoadMissingDefault(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	if _, err := Load(""); err != nil {
+		t.Fatalf("expected missing default config to be ignored, got %v", err)
+	}
+	if _, err := Load("other.json"); err == nil {
+		t.Fatalf("expected error for missing explicit config")
+	}
+}
+
+func TestReleaseRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jfrm.json")
+	data := []byte(`{"release":{"labels":{"Docs":"none","bug":"minor"},"conventionalCommits":false}}`)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rules, err := cfg.Release.Rules()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules.Labels["docs"] != version.BumpNone || rules.Labels["bug"] != version.BumpMinor {
+		t.Fatalf("configured labels not applied: %v", rules.Labels)
+	}
+	if rules.ConventionalCommits {
+		t.Fatalf("expected conventional commits to be disabled")
+	}
+
+	bad := Release{Labels: map[string]string{"x": "huge"}}
+	if _, err := bad.Rules(); err == nil {
+		t.Fatalf("expected error for unknown bump")
+	}
+}
+
+func TestVerifyPolicy(t *testing.T) {
+	lag := 1
+	v := Verify{MaxMinorLag: &lag, ReleaseBranches: []string{"master"}, Severities: map[string]string{"days-behind": "error", "vulnerability": "off"}}
+	p, err := v.Policy()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.MaxMinorLag != 1 || p.MaxDaysBehind != -1 || !p.IsReleaseBranch("master") {
+		t.Fatalf("configured policy not applied: %+v", p)
+	}
+	if p.Severities[policy.RuleDaysBehind] != policy.SeverityError || p.Severities[policy.RuleVulnerability] != policy.SeverityNone {
+		t.Fatalf("configured severities not applied: %v", p.Severities)
+	}
+
+	bad := Verify{Severities: map[string]string{"unknown": "error"}}
+	if _, err := bad.Policy(); err == nil {
+		t.Fatalf("expected error for unknown rule")
+	}
+}
+
+func TestGitHubEndpoints(t *testing.T) {
+	if h := (GitHub{}).Endpoints("ghe.example.com"); h.APIURL != "https://ghe.example.com/api/v3" {
+		t.Fatalf("expected endpoints derived from the remote host, got %+v", h)
+	}
+	g := GitHub{Host: "github.example.com", APIURL: "https://api.github.example.com"}
+	h := g.Endpoints("github.com")
+	if h.Name != "github.example.com" || h.APIURL != "https://api.github.example.com" || h.WebURL != "https://github.example.com" {
+		t.Fatalf("expected configured host and API URL, got %+v", h)
+	}
+}
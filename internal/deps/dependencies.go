@@ -1,35 +1,49 @@
 package deps
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/bhanurp/jfrm/internal/httpclient"
+	"github.com/bhanurp/jfrm/internal/registry"
 	"github.com/blang/semver/v4"
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 )
 
-var allowedDeps = map[string]bool{
-	"github.com/jfrog/jfrog-cli-core/v2":     true,
-	"github.com/jfrog/jfrog-client-go":       true,
-	"github.com/jfrog/jfrog-cli-artifactory": true,
-	"github.com/jfrog/jfrog-cli-security":    true,
-	"github.com/jfrog/build-info-go":         true,
-	"github.com/jfrog/gofrog":                true,
-}
-
-var dryRunReport []string
-
 // GetRepoName extracts the repository name from git remote (supports HTTPS and SSH).
 // It prefers the 'upstream' remote; falls back to 'origin' if not available.
 func GetRepoName() (string, error) {
+	remoteURL, err := repoRemoteURL()
+	if err != nil {
+		return "", err
+	}
+	return extractRepoSlug(remoteURL)
+}
+
+// GetRepoHost returns the host of the repository remote, e.g. "github.com" or the host of a
+// GitHub Enterprise Server
+func GetRepoHost() (string, error) {
+	remoteURL, err := repoRemoteURL()
+	if err != nil {
+		return "", err
+	}
+	return extractRepoHost(remoteURL)
+}
+
+// repoRemoteURL returns the URL of the upstream remote, falling back to origin
+func repoRemoteURL() (string, error) {
 	out, err := execCmd("git", "remote", "get-url", "upstream")
 	remoteURL := strings.TrimSpace(out)
 	if err != nil || remoteURL == "" {
@@ -40,16 +54,46 @@ func GetRepoName() (string, error) {
 		}
 		remoteURL = strings.TrimSpace(out)
 	}
-	return extractRepoSlug(remoteURL)
+	return remoteURL, nil
+}
+
+// extractRepoHost returns the host name of an HTTPS, SSH or scp-like remote URL
+func extractRepoHost(remoteURL string) (string, error) {
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err == nil && u.Hostname() != "" {
+			return strings.ToLower(u.Hostname()), nil
+		}
+	} else if _, rest, _ := strings.Cut(remoteURL, "@"); rest != "" {
+		// scp-like syntax: git@host:owner/repo.git
+		if host, _, ok := strings.Cut(rest, ":"); ok && host != "" {
+			return strings.ToLower(host), nil
+		}
+	}
+	return "", fmt.Errorf("could not parse host from remote: %s", remoteURL)
 }
 
-// extractRepoSlug normalizes a remote URL (HTTPS/SSH) to "owner/repo".
+// extractRepoSlug normalizes a remote URL (HTTPS/SSH) to its path on the host, "owner/repo"
+// on GitHub or "group/subgroup/repo" on GitLab.
 func extractRepoSlug(remoteURL string) (string, error) {
-	// Match the last two path segments before optional .git
 	// Examples handled:
 	//   https://github.com/owner/repo.git
 	//   ssh://git@github.com/owner/repo
 	//   git@github.com:owner/repo.git
+	//   https://gitlab.com/group/subgroup/repo.git
+	var path string
+	if strings.Contains(remoteURL, "://") {
+		if u, err := url.Parse(remoteURL); err == nil {
+			path = u.Path
+		}
+	} else if _, rest, _ := strings.Cut(remoteURL, "@"); rest != "" {
+		_, path, _ = strings.Cut(rest, ":")
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if strings.Contains(path, "/") {
+		return path, nil
+	}
+	// Fall back to the last two path segments before optional .git
 	re := regexp.MustCompile(`[:/]{1}([^/]+/[^/]+?)(?:\.git)?$`)
 	m := re.FindStringSubmatch(remoteURL)
 	if len(m) > 1 {
@@ -58,6 +102,51 @@ func extractRepoSlug(remoteURL string) (string, error) {
 	return "", fmt.Errorf("could not parse repository from remote: %s", remoteURL)
 }
 
+// DescribeTag returns the nearest tag made of prefix followed by a version that is reachable
+// from branch (tried on upstream, then origin, then locally) or from HEAD, skipping
+// pre-releases unless preReleases is set
+func DescribeTag(branch, prefix string, preReleases bool) (string, error) {
+	args := []string{"describe", "--tags", "--abbrev=0", "--match", prefix + "[0-9]*"}
+	if !preReleases {
+		args = append(args, "--exclude", prefix+"*-*")
+	}
+	var refs []string
+	if branch != "" {
+		refs = append(refs, "upstream/"+branch, "origin/"+branch, branch)
+	}
+	var out string
+	var err error
+	for _, ref := range append(refs, "HEAD") {
+		if out, err = execCmd("git", append(args, ref)...); err == nil {
+			return out, nil
+		}
+	}
+	return "", fmt.Errorf("git describe failed: %s", out)
+}
+
+// LocalTagDate returns the commit date of a tag in the local clone
+func LocalTagDate(tag string) (time.Time, error) {
+	out, err := execCmd("git", "log", "-1", "--format=%cI", tag+"^{commit}")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("git log failed for %s: %s", tag, out)
+	}
+	return time.Parse(time.RFC3339, out)
+}
+
+// ResolveLocalTag returns the object a tag points to in the local clone (the tag object of
+// an annotated tag) and the commit it dereferences to
+func ResolveLocalTag(tag string) (string, string, error) {
+	tagSHA, err := execCmd("git", "rev-parse", "refs/tags/"+tag)
+	if err != nil {
+		return "", "", fmt.Errorf("git rev-parse failed for %s: %s", tag, tagSHA)
+	}
+	commitSHA, err := execCmd("git", "rev-parse", "refs/tags/"+tag+"^{commit}")
+	if err != nil {
+		return "", "", fmt.Errorf("git rev-parse failed for %s: %s", tag, commitSHA)
+	}
+	return tagSHA, commitSHA, nil
+}
+
 // execCmd executes a command and returns the output
 func execCmd(name string, args ...string) (string, error) {
 	cmd := exec.Command(name, args...)
@@ -65,12 +154,33 @@ func execCmd(name string, args ...string) (string, error) {
 	return strings.TrimSpace(string(output)), err
 }
 
+// Update is a change of a module's required version
+type Update struct {
+	Module string
+	From   string
+	To     string
+}
+
 // GetDependencies reads and parses go.mod file
 func GetDependencies() (map[string]string, error) {
 	data, err := os.ReadFile("go.mod")
 	if err != nil {
 		return nil, err
 	}
+	return ParseDependencies(data)
+}
+
+// GetDependenciesAt parses go.mod as of the given git revision
+func GetDependenciesAt(rev string) (map[string]string, error) {
+	out, err := exec.Command("git", "show", rev+":go.mod").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod at %s: %w", rev, err)
+	}
+	return ParseDependencies(out)
+}
+
+// ParseDependencies parses go.mod content into a module → version map
+func ParseDependencies(data []byte) (map[string]string, error) {
 	modFile, err := modfile.Parse("go.mod", data, nil)
 	if err != nil {
 		return nil, err
@@ -82,14 +192,40 @@ func GetDependencies() (map[string]string, error) {
 	return deps, nil
 }
 
+// DiffDependencies lists the allowed modules whose version changed between two dependency sets, sorted by module
+func DiffDependencies(before, after map[string]string) []Update {
+	var updates []Update
+	for mod, to := range after {
+		if from, ok := before[mod]; ok && from != to && IsAllowedDependency(mod) {
+			updates = append(updates, Update{Module: mod, From: from, To: to})
+		}
+	}
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Module < updates[j].Module })
+	return updates
+}
+
 // GetLatestModuleVersion fetches the latest version for a module
 func GetLatestModuleVersion(module string) (string, error) {
-	fmt.Printf("Fetching latest version for module: %s\n", module)
+	latest, _, err := GetLatestModuleInfo(module)
+	return latest, err
+}
+
+// GetLatestModuleInfo fetches the latest version of a module and when it was published
+func GetLatestModuleInfo(module string) (string, time.Time, error) {
+	return GetLatestModuleInfoContext(context.Background(), module)
+}
+
+// GetLatestModuleInfoContext is GetLatestModuleInfo bound to ctx
+func GetLatestModuleInfoContext(ctx context.Context, module string) (string, time.Time, error) {
+	log.Printf("Fetching latest version for module: %s\n", module)
 	url := fmt.Sprintf("https://proxy.golang.org/%s/@latest", module)
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
+	}
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return "", time.Time{}, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -99,21 +235,135 @@ func GetLatestModuleVersion(module string) (string, error) {
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Error fetching latest version: %s\n", resp.Body)
-		return "", fmt.Errorf("unexpected response code 3: %d", resp.StatusCode)
+		log.Printf("Error fetching latest version: %s\n", resp.Body)
+		return "", time.Time{}, fmt.Errorf("unexpected response code 3: %d", resp.StatusCode)
 	}
 	var data struct {
-		Version string `json:"Version"`
+		Version string    `json:"Version"`
+		Time    time.Time `json:"Time"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", err
+		return "", time.Time{}, err
+	}
+	return data.Version, data.Time, nil
+}
+
+// ListModuleVersions returns the tagged versions of a module listed by the module proxy, in no particular order
+func ListModuleVersions(modulePath string) ([]string, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("https://proxy.golang.org/%s/@v/list", escaped)
+	resp, err := httpclient.Default().Get(context.Background(), url)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// GetModuleVersionTime returns when the module proxy first saw a version of a module
+func GetModuleVersionTime(modulePath, version string) (time.Time, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	url := fmt.Sprintf("https://proxy.golang.org/%s/@v/%s.info", escaped, version)
+	resp, err := httpclient.Default().Get(context.Background(), url)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+	}
+	var data struct {
+		Time time.Time `json:"Time"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return time.Time{}, err
+	}
+	return data.Time, nil
+}
+
+// GetLatestModFile fetches the go.mod of a module's latest version from the module proxy
+func GetLatestModFile(modulePath string) ([]byte, error) {
+	latest, err := GetLatestModuleVersion(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("https://proxy.golang.org/%s/@v/%s.mod", escaped, latest)
+	resp, err := httpclient.Default().Get(context.Background(), url)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// IsVersionAvailable reports whether the module proxy serves the given version
+func IsVersionAvailable(modulePath, version string) (bool, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return false, err
+	}
+	url := fmt.Sprintf("https://proxy.golang.org/%s/@v/%s.info", escaped, version)
+	resp, err := httpclient.Default().Get(context.Background(), url)
+	if err != nil {
+		return false, err
 	}
-	return data.Version, nil
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound, http.StatusGone:
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected response code: %d", resp.StatusCode)
 }
 
-// IsAllowedDependency checks if a dependency is in the allowed list
+// IsAllowedDependency checks if a dependency belongs to a registered repository
 func IsAllowedDependency(module string) bool {
-	return allowedDeps[module]
+	return registry.Current().IsManaged(module)
 }
 
 // IsNewerVersion checks if the latest version is newer than current
@@ -129,8 +379,7 @@ func IsNewerVersion(current, latest string) bool {
 // UpdateDependency updates a dependency to the latest version
 func UpdateDependency(module, currentVersion, latestVer string, dryRun bool) error {
 	if dryRun {
-		log.Printf("[Dry Run] Would update: %s -> %s\n", module, latestVer)
-		dryRunReport = append(dryRunReport, fmt.Sprintf("- `%s`: **%s → %s**", module, currentVersion, latestVer))
+		log.Printf("[Dry Run] Would update: %s %s -> %s\n", module, currentVersion, latestVer)
 		return nil
 	}
 	_, err := execCmd("go", "get", fmt.Sprintf("%s@%s", module, latestVer))
@@ -144,13 +393,3 @@ func GitExec(params ...string) error {
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
-
-// GetDryRunReport returns the dry run report
-func GetDryRunReport() []string {
-	return dryRunReport
-}
-
-// ClearDryRunReport clears the dry run report
-func ClearDryRunReport() {
-	dryRunReport = nil
-}
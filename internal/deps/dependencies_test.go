@@ -1,7 +1,9 @@
 package deps
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -43,10 +45,12 @@ func TestGetDependencies_Parse(t *testing.T) {
 
 func TestExtractRepoSlug(t *testing.T) {
 	cases := map[string]string{
-		"https://github.com/owner/repo.git": "owner/repo",
-		"https://github.com/owner/repo":     "owner/repo",
-		"ssh://git@github.com/owner/repo":   "owner/repo",
-		"git@github.com:owner/repo.git":     "owner/repo",
+		"https://github.com/owner/repo.git":     "owner/repo",
+		"https://github.com/owner/repo":         "owner/repo",
+		"ssh://git@github.com/owner/repo":       "owner/repo",
+		"git@github.com:owner/repo.git":         "owner/repo",
+		"https://gitlab.com/group/sub/repo.git": "group/sub/repo",
+		"git@gitlab.com:group/sub/repo.git":     "group/sub/repo",
 	}
 	for in, want := range cases {
 		got, err := extractRepoSlug(in)
@@ -58,3 +62,109 @@ func TestExtractRepoSlug(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractRepoHost(t *testing.T) {
+	cases := map[string]string{
+		"https://github.com/owner/repo.git":              "github.com",
+		"https://user:pw@GHE.example.com/owner/repo.git": "ghe.example.com",
+		"ssh://git@ghe.example.com:7999/owner/repo":      "ghe.example.com",
+		"git@ghe.example.com:owner/repo.git":             "ghe.example.com",
+	}
+	for in, want := range cases {
+		got, err := extractRepoHost(in)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	}
+	if _, err := extractRepoHost("/local/path/repo"); err == nil {
+		t.Fatalf("expected error for a local path")
+	}
+}
+
+func TestDiffDependencies(t *testing.T) {
+	before := map[string]string{
+		"github.com/jfrog/gofrog":          "v1.7.5",
+		"github.com/jfrog/jfrog-client-go": "v1.40.0",
+		"example.com/other":                "v1.0.0",
+	}
+	after := map[string]string{
+		"github.com/jfrog/gofrog":          "v1.7.6",
+		"github.com/jfrog/jfrog-client-go": "v1.40.0",
+		"example.com/other":                "v1.1.0",
+	}
+	got := DiffDependencies(before, after)
+	if len(got) != 1 || got[0] != (Update{Module: "github.com/jfrog/gofrog", From: "v1.7.5", To: "v1.7.6"}) {
+		t.Fatalf("unexpected updates: %+v", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	current := map[string]string{
+		"github.com/jfrog/jfrog-client-go":   "v1.49.0",
+		"github.com/jfrog/gofrog":            "v1.7.5",
+		"github.com/jfrog/build-info-go":     "v1.9.1-0.20250101000000-abcdefabcdef",
+		"github.com/jfrog/jfrog-cli-core/v2": "v2.58.0",
+		"example.com/other":                  "v0.1.0",
+	}
+	latest := map[string]string{
+		"github.com/jfrog/jfrog-client-go": "v1.50.0",
+		"github.com/jfrog/gofrog":          "v1.7.6",
+		"github.com/jfrog/build-info-go":   "v1.10.0",
+	}
+	got := Check(current, func(mod string) (string, error) {
+		if v, ok := latest[mod]; ok {
+			return v, nil
+		}
+		return "", errors.New("not found")
+	})
+	want := []Status{
+		{Module: "github.com/jfrog/build-info-go", Current: "v1.9.1-0.20250101000000-abcdefabcdef", Latest: "v1.10.0", Status: StatusOutdated, UpdateType: "minor", PseudoVersion: true},
+		{Module: "github.com/jfrog/gofrog", Current: "v1.7.5", Latest: "v1.7.6", Status: StatusOutdated, UpdateType: "patch"},
+		{Module: "github.com/jfrog/jfrog-cli-core/v2", Current: "v2.58.0", Status: StatusError, Error: "not found"},
+		{Module: "github.com/jfrog/jfrog-client-go", Current: "v1.49.0", Latest: "v1.50.0", Status: StatusOutdated, UpdateType: "minor"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d statuses, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("status %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if Outdated(got) != 3 {
+		t.Fatalf("expected 3 outdated, got %d", Outdated(got))
+	}
+}
+
+func TestGetCachedModuleInfo(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GOMODCACHE", dir)
+	versions := filepath.Join(dir, "cache", "download", "github.com", "jfrog", "gofrog", "@v")
+	if err := os.MkdirAll(versions, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"v1.7.5.info":      `{"Version":"v1.7.5","Time":"2024-10-01T00:00:00Z"}`,
+		"v1.7.6.info":      `{"Version":"v1.7.6","Time":"2025-01-15T00:00:00Z"}`,
+		"v1.8.0-rc.1.info": `{"Version":"v1.8.0-rc.1","Time":"2025-02-01T00:00:00Z"}`,
+		"v1.7.6.mod":       "module github.com/jfrog/gofrog\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(versions, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v, published, err := GetCachedModuleInfo("github.com/jfrog/gofrog")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != "v1.7.6" || published.Format("2006-01-02") != "2025-01-15" {
+		t.Fatalf("unexpected cached version: %s %s", v, published)
+	}
+	if _, _, err := GetCachedModuleInfo("github.com/jfrog/missing"); err == nil {
+		t.Fatalf("expected error for a module that is not cached")
+	}
+}
@@ -0,0 +1,85 @@
+package deps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ModCacheDir returns the module cache directory ($GOMODCACHE, as reported by go env)
+func ModCacheDir() (string, error) {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir, nil
+	}
+	out, err := execCmd("go", "env", "GOMODCACHE")
+	if err != nil {
+		return "", err
+	}
+	if dir := strings.TrimSpace(out); dir != "" {
+		return dir, nil
+	}
+	return "", fmt.Errorf("GOMODCACHE is not set")
+}
+
+// GetCachedModuleInfo returns the highest version of a module present in the local module
+// download cache, preferring releases over pre-releases like the proxy's @latest does
+func GetCachedModuleInfo(modulePath string) (string, time.Time, error) {
+	dir, err := ModCacheDir()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	versionsDir := filepath.Join(dir, "cache", "download", filepath.FromSlash(escaped), "@v")
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%s is not in the module cache: %w", modulePath, err)
+	}
+
+	var release, prerelease string
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasSuffix(name, ".info") {
+			continue
+		}
+		v, err := module.UnescapeVersion(strings.TrimSuffix(name, ".info"))
+		if err != nil || !semver.IsValid(v) {
+			continue
+		}
+		if semver.Prerelease(v) == "" {
+			if release == "" || semver.Compare(v, release) > 0 {
+				release = v
+			}
+		} else if prerelease == "" || semver.Compare(v, prerelease) > 0 {
+			prerelease = v
+		}
+	}
+	latest := release
+	if latest == "" {
+		latest = prerelease
+	}
+	if latest == "" {
+		return "", time.Time{}, fmt.Errorf("no versions of %s in the module cache", modulePath)
+	}
+
+	escapedVersion, err := module.EscapeVersion(latest)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	var info struct {
+		Version string    `json:"Version"`
+		Time    time.Time `json:"Time"`
+	}
+	if data, err := os.ReadFile(filepath.Join(versionsDir, escapedVersion+".info")); err == nil {
+		_ = json.Unmarshal(data, &info)
+	}
+	return latest, info.Time, nil
+}
@@ -0,0 +1,83 @@
+package deps
+
+import (
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/mod/module"
+)
+
+// Dependency statuses reported by Check
+const (
+	StatusUpToDate = "up-to-date"
+	StatusOutdated = "outdated"
+	StatusError    = "error"
+)
+
+// Status describes how a managed dependency compares to its latest published version
+type Status struct {
+	Module        string `json:"module"`
+	Current       string `json:"current"`
+	Latest        string `json:"latest,omitempty"`
+	Status        string `json:"status"`
+	UpdateType    string `json:"updateType,omitempty"`
+	PseudoVersion bool   `json:"pseudoVersion"`
+	Error         string `json:"error,omitempty"`
+	// Stale marks a latest version read from a local cache in offline mode
+	Stale bool `json:"stale"`
+}
+
+// LatestFunc resolves the latest version of a module
+type LatestFunc func(module string) (string, error)
+
+// Check compares the managed dependencies against their latest versions, sorted by module path
+func Check(dependencies map[string]string, latest LatestFunc) []Status {
+	var statuses []Status
+	for mod, current := range dependencies {
+		if !IsAllowedDependency(mod) {
+			continue
+		}
+		s := Status{Module: mod, Current: current, Status: StatusUpToDate, PseudoVersion: module.IsPseudoVersion(current)}
+		latestVer, err := latest(mod)
+		if err != nil {
+			s.Status = StatusError
+			s.Error = err.Error()
+		} else {
+			s.Latest = latestVer
+			if IsNewerVersion(current, latestVer) {
+				s.Status = StatusOutdated
+				s.UpdateType = UpdateType(current, latestVer)
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Module < statuses[j].Module })
+	return statuses
+}
+
+// UpdateType classifies the update from current to latest as major, minor or patch
+func UpdateType(current, latest string) string {
+	c, err1 := semver.ParseTolerant(current)
+	l, err2 := semver.ParseTolerant(latest)
+	if err1 != nil || err2 != nil || !l.GT(c) {
+		return ""
+	}
+	switch {
+	case l.Major != c.Major:
+		return "major"
+	case l.Minor != c.Minor:
+		return "minor"
+	}
+	return "patch"
+}
+
+// Outdated returns the number of statuses with an update available
+func Outdated(statuses []Status) int {
+	n := 0
+	for _, s := range statuses {
+		if s.Status == StatusOutdated {
+			n++
+		}
+	}
+	return n
+}
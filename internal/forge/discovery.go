@@ -0,0 +1,149 @@
+package forge
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/version"
+)
+
+// Release discovery modes
+const (
+	// DiscoverRelease picks the highest published release, falling back to tags when the
+	// repository has no matching release
+	DiscoverRelease = "release"
+	// DiscoverTag picks the highest tag, for repositories that publish tags without releases
+	DiscoverTag = "tag"
+	// DiscoverGit picks the nearest tag on the base branch of the local clone
+	DiscoverGit = "git"
+)
+
+// DefaultTagPrefix is the part of release tags before the version
+const DefaultTagPrefix = "v"
+
+// Discovery configures how the latest release of a repository is found. Only tags made of
+// the prefix followed by a semantic version are considered.
+type Discovery struct {
+	// Mode is DiscoverRelease (default), DiscoverTag or DiscoverGit
+	Mode string
+	// Prefix replaces DefaultTagPrefix, e.g. "api/v" for a module in a subdirectory
+	Prefix string
+	// PreReleases includes pre-releases
+	PreReleases bool
+	// Branch is the base branch searched by git describe (default HEAD)
+	Branch string
+}
+
+// Validate checks the discovery mode
+func (d Discovery) Validate() error {
+	switch d.mode() {
+	case DiscoverRelease, DiscoverTag, DiscoverGit:
+		return nil
+	}
+	return fmt.Errorf("unsupported discovery mode %q; expected %s, %s or %s", d.Mode, DiscoverRelease, DiscoverTag, DiscoverGit)
+}
+
+func (d Discovery) mode() string {
+	if d.Mode == "" {
+		return DiscoverRelease
+	}
+	return strings.ToLower(d.Mode)
+}
+
+func (d Discovery) prefix() string {
+	if d.Prefix == "" {
+		return DefaultTagPrefix
+	}
+	return d.Prefix
+}
+
+// Version returns the version part of a release tag
+func (d Discovery) Version(tag string) string {
+	return strings.TrimPrefix(tag, d.prefix())
+}
+
+// Tag returns the release tag of a version
+func (d Discovery) Tag(version string) string {
+	return d.prefix() + version
+}
+
+// Discover returns the latest release tag of repo and when it was published. When the forge
+// API fails, the tag is looked up in the local clone with git describe; local reports whether
+// the tag comes from the local clone.
+func Discover(f Forge, repo string, d Discovery) (tag string, published time.Time, local bool, err error) {
+	if err := d.Validate(); err != nil {
+		return "", time.Time{}, false, err
+	}
+	switch d.mode() {
+	case DiscoverGit:
+		tag, published, err = d.fromGit()
+		return tag, published, true, err
+	case DiscoverTag:
+		tag, published, err = d.fromTags(f, repo)
+	default:
+		tag, published, err = d.fromReleases(f, repo)
+	}
+	if err == nil {
+		log.Printf("Latest release of %s: %s\n", repo, tag)
+		return tag, published, false, nil
+	}
+	log.Printf("⚠️  %v; looking for the latest tag in the local clone", err)
+	tag, published, gitErr := d.fromGit()
+	if gitErr != nil {
+		return "", time.Time{}, false, fmt.Errorf("%w; %v", err, gitErr)
+	}
+	return tag, published, true, nil
+}
+
+// fromReleases picks the highest published release, skipping drafts and, unless included,
+// releases flagged or versioned as pre-releases
+func (d Discovery) fromReleases(f Forge, repo string) (string, time.Time, error) {
+	releases, err := f.Releases(repo)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to list releases of %s: %w", repo, err)
+	}
+	published := make(map[string]time.Time, len(releases))
+	var tags []string
+	for _, r := range releases {
+		if r.Draft || (r.Prerelease && !d.PreReleases) {
+			continue
+		}
+		tags = append(tags, r.TagName)
+		published[r.TagName] = r.PublishedAt
+	}
+	if tag, ok := version.Latest(tags, d.prefix(), d.PreReleases); ok {
+		return tag, published[tag], nil
+	}
+	log.Printf("No %s* release found for %s; looking for tags", d.prefix(), repo)
+	return d.fromTags(f, repo)
+}
+
+// fromTags picks the highest tag, dated by the commit it points to
+func (d Discovery) fromTags(f Forge, repo string) (string, time.Time, error) {
+	tags, err := f.Tags(repo)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to list tags of %s: %w", repo, err)
+	}
+	tag, ok := version.Latest(tags, d.prefix(), d.PreReleases)
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("no %s* release tag found for %s", d.prefix(), repo)
+	}
+	published, err := f.TagDate(repo, tag)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return tag, published, nil
+}
+
+// fromGit picks the nearest tag on the base branch of the local clone
+func (d Discovery) fromGit() (string, time.Time, error) {
+	tag, err := deps.DescribeTag(d.Branch, d.prefix(), d.PreReleases)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to find latest tag: %w", err)
+	}
+	published, err := deps.LocalTagDate(tag)
+	return tag, published, err
+}
@@ -0,0 +1,201 @@
+package forge
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/github"
+)
+
+// Forge kinds
+const (
+	KindGitHub = "github"
+	KindGitLab = "gitlab"
+	KindGitea  = "gitea"
+)
+
+// Kinds lists the supported forges
+var Kinds = []string{KindGitHub, KindGitLab, KindGitea}
+
+// Change is a pull request (GitHub, Gitea) or merge request (GitLab) to open or update
+type Change struct {
+	Branch string
+	Base   string
+	Title  string
+	Body   string
+}
+
+// ChangeStatus is the state of an opened change: State is open or closed, and a merged change
+// is closed with Merged set, as on GitHub
+type ChangeStatus struct {
+	Number int    `json:"number"`
+	State  string `json:"state"`
+	Merged bool   `json:"merged"`
+	URL    string `json:"url"`
+}
+
+// Forge is the code hosting service of a repository. Repositories are identified by their
+// path on the forge, e.g. "owner/repo" or "group/subgroup/repo" on GitLab. Merged changes
+// are returned as github.PullRequest, the model shared by release planning and reports.
+type Forge interface {
+	// Kind returns the forge kind, e.g. KindGitHub
+	Kind() string
+	// Token returns the token used for requests, or "" when requests are anonymous
+	Token() (string, error)
+	// RepoURL returns the web URL of a repository
+	RepoURL(repo string) string
+	// Releases returns the most recent releases, including drafts and pre-releases
+	Releases(repo string) ([]github.ReleaseSummary, error)
+	// Tags returns the names of the most recent tags
+	Tags(repo string) ([]string, error)
+	// TagDate returns the date of the commit a tag points to
+	TagDate(repo, tag string) (time.Time, error)
+	// ReleaseDate returns when the release of tag was published
+	ReleaseDate(repo, tag string) (time.Time, error)
+	// ResolveTag returns the object the tag points to and the commit it dereferences to
+	ResolveTag(repo, tag string) (*github.TagRef, error)
+	// MergedChanges returns the changes merged into the repository's base branch after since
+	MergedChanges(repo string, since time.Time) ([]github.PullRequest, error)
+	// FindOpenChange returns the number of the open change from branch into base, or 0
+	FindOpenChange(repo, branch, base string) (int, error)
+	// CreateChange opens a change and returns its number
+	CreateChange(repo string, c Change) (int, error)
+	// UpdateChange replaces the title and body of an open change
+	UpdateChange(repo string, number int, c Change) error
+	// ChangeStatus returns the state of a change
+	ChangeStatus(repo string, number int) (*ChangeStatus, error)
+	// ChangeChecks returns the CI checks, review decision and mergeability of a change
+	ChangeChecks(repo string, number int) (*github.PullRequestStatus, error)
+	// FindRelease returns the release of tag, draft or not, or nil when there is none
+	FindRelease(repo, tag string) (*github.Release, error)
+	// CreateRelease creates a release; drafts stay unpublished until UpdateRelease clears Draft
+	CreateRelease(repo string, rel github.Release) (*github.Release, error)
+	// UpdateRelease updates a release found or created before
+	UpdateRelease(repo string, rel github.Release) (*github.Release, error)
+	// GetFile returns a file on ref and its revision marker, or nil content when it does not exist
+	GetFile(repo, path, ref string) ([]byte, string, error)
+	// PutFile commits a file to a branch and returns the commit SHA; rev is the marker from
+	// GetFile, empty when creating the file
+	PutFile(repo, path, branch, message string, content []byte, rev string) (string, error)
+}
+
+// ReleaseInfo is a release with its tag resolved to a commit
+type ReleaseInfo struct {
+	github.TagRef
+	// Version is the tag without the release tag prefix
+	Version     string    `json:"version"`
+	PublishedAt time.Time `json:"publishedAt"`
+	// Local marks a tag resolved in the local clone because the forge API was unavailable
+	Local bool `json:"local"`
+}
+
+// String describes the release for logs, naming the tag object only for annotated tags
+func (r ReleaseInfo) String() string {
+	if r.Annotated() {
+		return fmt.Sprintf("%s (tag object %s, commit %s) released on [%s]", r.Tag, r.TagSHA, r.CommitSHA, r.PublishedAt.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s (commit %s) released on [%s]", r.Tag, r.CommitSHA, r.PublishedAt.Format(time.RFC3339))
+}
+
+// ResolveTag resolves tag through the forge API, falling back to the local clone when the
+// API is unavailable; local reports whether the fallback was used
+func ResolveTag(f Forge, repo, tag string) (ref *github.TagRef, local bool, err error) {
+	ref, err = f.ResolveTag(repo, tag)
+	if err == nil {
+		return ref, false, nil
+	}
+	tagSHA, commitSHA, localErr := deps.ResolveLocalTag(tag)
+	if localErr != nil {
+		return nil, false, fmt.Errorf("%w; %v", err, localErr)
+	}
+	log.Printf("⚠️  Could not resolve tag %s via the %s API (%v); using the local clone", tag, f.Kind(), err)
+	return &github.TagRef{Tag: tag, TagSHA: tagSHA, CommitSHA: commitSHA}, true, nil
+}
+
+// LatestReleaseInfo discovers the latest release of repo and resolves its tag to a commit
+func LatestReleaseInfo(f Forge, repo string, d Discovery) (*ReleaseInfo, error) {
+	tag, published, local, err := Discover(f, repo, d)
+	if err != nil {
+		return nil, err
+	}
+	ref := &github.TagRef{Tag: tag}
+	if local {
+		ref.TagSHA, ref.CommitSHA, err = deps.ResolveLocalTag(tag)
+	} else {
+		ref, local, err = ResolveTag(f, repo, tag)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tag %s: %w", tag, err)
+	}
+	return &ReleaseInfo{TagRef: *ref, Version: d.Version(tag), PublishedAt: published, Local: local}, nil
+}
+
+// Detect returns the forge kind of a remote host: GitHub for github.com, GitLab and Gitea
+// for hosts named after them (and codeberg.org), and GitHub Enterprise Server otherwise
+func Detect(host string) string {
+	host = strings.ToLower(host)
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return KindGitLab
+	case strings.Contains(host, "gitea"), host == "codeberg.org":
+		return KindGitea
+	}
+	return KindGitHub
+}
+
+// ParseKind validates a forge kind
+func ParseKind(kind string) (string, error) {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	for _, k := range Kinds {
+		if k == kind {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported forge %q; expected one of %s", kind, strings.Join(Kinds, ", "))
+}
+
+// New returns the forge of the given kind on host. apiURL overrides the API root derived from
+// host; GitHub endpoints come from github.CurrentHost instead.
+func New(kind, host, apiURL string) (Forge, error) {
+	switch kind {
+	case KindGitHub:
+		return GitHub{}, nil
+	case KindGitLab:
+		return NewGitLab(host, apiURL), nil
+	case KindGitea:
+		return NewGitea(host, apiURL), nil
+	}
+	_, err := ParseKind(kind)
+	return nil, err
+}
+
+var (
+	currentMu sync.RWMutex
+	current   Forge = GitHub{}
+)
+
+// Current returns the forge of the repository jfrm works on
+func Current() Forge {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// SetCurrent replaces the forge used by all commands in this process
+func SetCurrent(f Forge) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = f
+}
+
+// apiRoot returns apiURL without a trailing slash, or https://host + path when it is empty
+func apiRoot(host, apiURL, path string) string {
+	if apiURL != "" {
+		return strings.TrimSuffix(apiURL, "/")
+	}
+	return "https://" + host + path
+}
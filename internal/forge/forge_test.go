@@ -0,0 +1,208 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/github"
+)
+
+func TestDetect(t *testing.T) {
+	cases := map[string]string{
+		"github.com":         KindGitHub,
+		"ghe.example.com":    KindGitHub,
+		"gitlab.com":         KindGitLab,
+		"gitlab.example.com": KindGitLab,
+		"gitea.example.com":  KindGitea,
+		"codeberg.org":       KindGitea,
+	}
+	for host, want := range cases {
+		if got := Detect(host); got != want {
+			t.Errorf("Detect(%s) = %s, want %s", host, got, want)
+		}
+	}
+	if _, err := ParseKind("bitbucket"); err == nil {
+		t.Errorf("expected an error for an unsupported forge")
+	}
+}
+
+func TestGitLabMergeRequests(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fsub%2Frepo/merge_requests" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		switch r.Method {
+		case "GET":
+			if r.URL.Query().Get("state") != "merged" || r.URL.Query().Get("target_branch") != "dev" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"iid": 3, "title": "feat: new", "author": map[string]string{"username": "dev"}, "labels": []string{"new feature"}, "merged_at": since.Add(time.Hour)},
+				{"iid": 2, "title": "old", "merged_at": since.Add(-time.Hour)},
+			})
+		case "POST":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["source_branch"] != "update" || body["target_branch"] != "dev" {
+				t.Errorf("unexpected MR body %v", body)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]int{"iid": 4})
+		}
+	}))
+	defer srv.Close()
+
+	g := NewGitLab("gitlab.example.com", srv.URL+"/api/v4/")
+	changes, err := g.MergedChanges("group/sub/repo", since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Number != 3 || changes[0].Author != "dev" || !changes[0].HasLabel("new feature") {
+		t.Fatalf("unexpected merged changes %+v", changes)
+	}
+	number, err := g.CreateChange("group/sub/repo", Change{Branch: "update", Base: "dev", Title: "Update"})
+	if err != nil || number != 4 {
+		t.Fatalf("expected MR !4, got %d (err %v)", number, err)
+	}
+}
+
+func TestGitLabMergedState(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/owner%2Frepo/merge_requests/5", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"iid": 5, "state": "merged", "sha": "abc", "detailed_merge_status": "not_open"})
+	})
+	mux.HandleFunc("/api/v4/projects/owner%2Frepo/merge_requests/5/approvals", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]bool{"approved": true})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	g := NewGitLab("gitlab.example.com", srv.URL+"/api/v4")
+	status, err := g.ChangeStatus("owner/repo", 5)
+	if err != nil || status.State != "closed" || !status.Merged {
+		t.Fatalf("expected a closed, merged MR, got %+v (err %v)", status, err)
+	}
+	checks, err := g.ChangeChecks("owner/repo", 5)
+	if err != nil || checks.State != status.State || checks.Merged != status.Merged {
+		t.Fatalf("checks report a different state than the status: %+v (err %v)", checks, err)
+	}
+}
+
+func TestGitLabDraftReleaseIsCreatedOnPublish(t *testing.T) {
+	var created map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "POST" && r.URL.Path == "/api/v4/projects/owner/repo/releases":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_ = json.NewEncoder(w).Encode(map[string]string{"tag_name": created["tag_name"]})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	g := NewGitLab("gitlab.example.com", srv.URL+"/api/v4")
+	draft, err := g.CreateRelease("owner/repo", github.Release{TagName: "v1.1.0", TargetCommitish: "main", Body: "notes", Draft: true})
+	if err != nil || created != nil {
+		t.Fatalf("expected the draft to stay local, got %v (err %v)", created, err)
+	}
+	draft.Draft = false
+	if _, err := g.UpdateRelease("owner/repo", *draft); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created["tag_name"] != "v1.1.0" || created["ref"] != "main" || created["description"] != "notes" {
+		t.Fatalf("unexpected release payload %v", created)
+	}
+}
+
+func TestGitLabResolveAnnotatedTag(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/owner%2Frepo/repository/tags/v1.0.0" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"target": "tag111", "commit": map[string]string{"id": "commit222"}})
+	}))
+	defer srv.Close()
+
+	ref, err := NewGitLab("gitlab.example.com", srv.URL+"/api/v4").ResolveTag("owner/repo", "v1.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref.TagSHA != "tag111" || ref.CommitSHA != "commit222" || !ref.Annotated() {
+		t.Fatalf("unexpected tag ref %+v", ref)
+	}
+	info := ReleaseInfo{TagRef: *ref, PublishedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if got, want := info.String(), "v1.0.0 (tag object tag111, commit commit222) released on [2025-01-01T00:00:00Z]"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestGiteaChanges(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"number": 1, "head": map[string]string{"ref": "other"}, "base": map[string]string{"ref": "dev"}},
+			{"number": 2, "head": map[string]string{"ref": "update"}, "base": map[string]string{"ref": "dev"}},
+		})
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/pulls/2", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"number": 2, "state": "closed", "merged": true, "html_url": "https://gitea.example.com/owner/repo/pulls/2"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	g := NewGitea("gitea.example.com", srv.URL+"/api/v1")
+	number, err := g.FindOpenChange("owner/repo", "update", "dev")
+	if err != nil || number != 2 {
+		t.Fatalf("expected PR #2, got %d (err %v)", number, err)
+	}
+	status, err := g.ChangeStatus("owner/repo", 2)
+	if err != nil || !status.Merged || status.State != "closed" {
+		t.Fatalf("expected a merged PR, got %+v (err %v)", status, err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	published := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	committed := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	var releases []map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/repos/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(releases)
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/tags", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]map[string]string{{"name": "v1.9.0"}, {"name": "v1.10.0"}, {"name": "v2.0.0-rc.1"}, {"name": "latest"}})
+	})
+	mux.HandleFunc("/api/v1/repos/owner/repo/tags/v1.10.0", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": "v1.10.0", "commit": map[string]interface{}{"sha": "abc", "created": committed}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	g := NewGitea("gitea.example.com", srv.URL+"/api/v1")
+
+	// A repository publishing tags only falls back to the highest final tag
+	tag, date, local, err := Discover(g, "owner/repo", Discovery{})
+	if err != nil || tag != "v1.10.0" || !date.Equal(committed) || local {
+		t.Fatalf("expected v1.10.0 from tags, got %s %s local=%v (err %v)", tag, date, local, err)
+	}
+
+	releases = []map[string]interface{}{
+		{"tag_name": "v1.12.0", "draft": true},
+		{"tag_name": "v1.11.0-rc.1", "prerelease": true, "published_at": published},
+		{"tag_name": "v1.10.0", "published_at": published},
+	}
+	if tag, _, _, err = Discover(g, "owner/repo", Discovery{}); err != nil || tag != "v1.10.0" {
+		t.Fatalf("expected the drafts and pre-releases to be skipped, got %s (err %v)", tag, err)
+	}
+	if tag, date, _, err = Discover(g, "owner/repo", Discovery{PreReleases: true}); err != nil || tag != "v1.11.0-rc.1" || !date.Equal(published) {
+		t.Fatalf("expected v1.11.0-rc.1, got %s %s (err %v)", tag, date, err)
+	}
+	if _, _, _, err = Discover(g, "owner/repo", Discovery{Mode: "svn"}); err == nil {
+		t.Fatalf("expected an error for an unsupported mode")
+	}
+}
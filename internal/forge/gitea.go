@@ -0,0 +1,392 @@
+package forge
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/auth"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/httpclient"
+	"github.com/bhanurp/jfrm/internal/registry"
+)
+
+// Gitea is a Gitea or Forgejo instance such as codeberg.org, using the REST API v1, whose
+// release and contents payloads follow GitHub's
+type Gitea struct {
+	Host   string
+	APIURL string
+}
+
+// NewGitea returns the Gitea forge on host; apiURL defaults to https://host/api/v1
+func NewGitea(host, apiURL string) *Gitea {
+	return &Gitea{Host: host, APIURL: apiRoot(host, apiURL, "/api/v1")}
+}
+
+// Kind returns KindGitea
+func (g *Gitea) Kind() string { return KindGitea }
+
+// Token returns the token from GITEA_TOKEN, the configuration or the git credential helper
+func (g *Gitea) Token() (string, error) {
+	return auth.Current().ForgeToken(g.Host, "GITEA_TOKEN")
+}
+
+// RepoURL returns the web URL of a repository
+func (g *Gitea) RepoURL(repo string) string {
+	return "https://" + g.Host + "/" + repo
+}
+
+// repo returns the API root of a repository
+func (g *Gitea) repo(repo string) string {
+	return g.APIURL + "/repos/" + repo
+}
+
+// request sends an API request authenticated with an access token
+func (g *Gitea) request(method, url string, body, out interface{}) error {
+	return httpclient.DoJSON(method, url, g.authorize, body, out)
+}
+
+// authorize adds the token to a request, when there is one
+func (g *Gitea) authorize(req *http.Request) error {
+	token, err := g.Token()
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	return nil
+}
+
+// poll sends a GET request that bypasses fresh cache entries
+func (g *Gitea) poll(url string, out interface{}) error {
+	return httpclient.DoJSON("GET", url, httpclient.NoCache(g.authorize), nil, out)
+}
+
+// giteaRelease is a release as returned by the Releases API
+type giteaRelease struct {
+	github.Release
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Releases returns the most recent releases
+func (g *Gitea) Releases(repo string) ([]github.ReleaseSummary, error) {
+	var releases []github.ReleaseSummary
+	if err := g.request("GET", g.repo(repo)+"/releases?limit=50", nil, &releases); err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	return releases, nil
+}
+
+// Tags returns the most recent tags
+func (g *Gitea) Tags(repo string) ([]string, error) {
+	var tags []giteaTag
+	if err := g.request("GET", g.repo(repo)+"/tags?limit=50", nil, &tags); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
+
+// TagDate returns the date of the commit a tag points to
+func (g *Gitea) TagDate(repo, tag string) (time.Time, error) {
+	t, err := g.tag(repo, tag)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Commit.Created, nil
+}
+
+// ReleaseDate returns when the release of tag was published
+func (g *Gitea) ReleaseDate(repo, tag string) (time.Time, error) {
+	var rel giteaRelease
+	if err := g.request("GET", g.repo(repo)+"/releases/tags/"+url.PathEscape(tag), nil, &rel); err != nil {
+		return time.Time{}, fmt.Errorf("failed to fetch release %s: %w", tag, err)
+	}
+	return rel.PublishedAt, nil
+}
+
+// giteaTag is a tag as returned by the Tags API; id is the tag object of an annotated tag
+// and the commit of a lightweight one
+type giteaTag struct {
+	Name   string `json:"name"`
+	ID     string `json:"id"`
+	Commit struct {
+		SHA     string    `json:"sha"`
+		Created time.Time `json:"created"`
+	} `json:"commit"`
+}
+
+// tag fetches a single tag
+func (g *Gitea) tag(repo, tag string) (*giteaTag, error) {
+	var t giteaTag
+	if err := g.request("GET", g.repo(repo)+"/tags/"+url.PathEscape(tag), nil, &t); err != nil {
+		return nil, fmt.Errorf("failed to fetch tag %s: %w", tag, err)
+	}
+	if t.Commit.SHA == "" {
+		return nil, fmt.Errorf("unexpected: sha is empty")
+	}
+	return &t, nil
+}
+
+// ResolveTag resolves a tag to its tag object and commit
+func (g *Gitea) ResolveTag(repo, tag string) (*github.TagRef, error) {
+	t, err := g.tag(repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	if t.ID == "" {
+		t.ID = t.Commit.SHA
+	}
+	return &github.TagRef{Tag: tag, TagSHA: t.ID, CommitSHA: t.Commit.SHA}, nil
+}
+
+// giteaPullRequest is a pull request as returned by the Pulls API
+type giteaPullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+	HTMLURL string `json:"html_url"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Head struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	MergedAt *time.Time `json:"merged_at"`
+}
+
+// MergedChanges returns the pull requests merged into the base branch after since
+func (g *Gitea) MergedChanges(repo string, since time.Time) ([]github.PullRequest, error) {
+	base := registry.Current().BaseBranch(repo)
+	u := g.repo(repo) + "/pulls?state=closed&sort=recentupdate&limit=50"
+	log.Printf("Fetching all closed PRs for repo: %s URL used : %s\n", repo, u)
+	var prs []giteaPullRequest
+	if err := g.request("GET", u, nil, &prs); err != nil {
+		return nil, fmt.Errorf("failed to fetch PRs: %w", err)
+	}
+	var changes []github.PullRequest
+	for _, pr := range prs {
+		if !pr.Merged || pr.MergedAt == nil || !pr.MergedAt.After(since) || pr.Base.Ref != base {
+			continue
+		}
+		var labels []string
+		for _, label := range pr.Labels {
+			labels = append(labels, label.Name)
+		}
+		changes = append(changes, github.PullRequest{
+			Number:   pr.Number,
+			Title:    pr.Title,
+			Body:     pr.Body,
+			Author:   pr.User.Login,
+			Labels:   labels,
+			MergedAt: *pr.MergedAt,
+		})
+	}
+	return changes, nil
+}
+
+// FindOpenChange returns the open pull request from branch into base
+func (g *Gitea) FindOpenChange(repo, branch, base string) (int, error) {
+	var prs []giteaPullRequest
+	if err := g.request("GET", g.repo(repo)+"/pulls?state=open&limit=50", nil, &prs); err != nil {
+		return 0, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	for _, pr := range prs {
+		if pr.Head.Ref == branch && pr.Base.Ref == base {
+			return pr.Number, nil
+		}
+	}
+	return 0, nil
+}
+
+// CreateChange opens a pull request
+func (g *Gitea) CreateChange(repo string, c Change) (int, error) {
+	var pr giteaPullRequest
+	body := map[string]string{"head": c.Branch, "base": c.Base, "title": c.Title, "body": c.Body}
+	if err := g.request("POST", g.repo(repo)+"/pulls", body, &pr); err != nil {
+		return 0, fmt.Errorf("failed to create PR: %w", err)
+	}
+	log.Printf("PR created successfully: #%d\n", pr.Number)
+	return pr.Number, nil
+}
+
+// UpdateChange replaces the title and body of a pull request
+func (g *Gitea) UpdateChange(repo string, number int, c Change) error {
+	body := map[string]string{"title": c.Title, "body": c.Body}
+	if err := g.request("PATCH", fmt.Sprintf("%s/pulls/%d", g.repo(repo), number), body, nil); err != nil {
+		return fmt.Errorf("failed to update PR #%d: %w", number, err)
+	}
+	return nil
+}
+
+// ChangeStatus returns the state of a pull request
+func (g *Gitea) ChangeStatus(repo string, number int) (*ChangeStatus, error) {
+	var pr giteaPullRequest
+	if err := g.request("GET", fmt.Sprintf("%s/pulls/%d", g.repo(repo), number), nil, &pr); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR #%d: %w", number, err)
+	}
+	return &ChangeStatus{Number: pr.Number, State: pr.State, Merged: pr.Merged, URL: pr.HTMLURL}, nil
+}
+
+// ChangeChecks returns the commit statuses, reviews and mergeability of a pull request
+func (g *Gitea) ChangeChecks(repo string, number int) (*github.PullRequestStatus, error) {
+	var pr struct {
+		giteaPullRequest
+		Mergeable bool `json:"mergeable"`
+	}
+	if err := g.poll(fmt.Sprintf("%s/pulls/%d", g.repo(repo), number), &pr); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR #%d: %w", number, err)
+	}
+	status := &github.PullRequestStatus{
+		PullRequestState: github.PullRequestState{Number: pr.Number, State: pr.State, Merged: pr.Merged, HTMLURL: pr.HTMLURL},
+		HeadSHA:          pr.Head.SHA,
+		Checks:           []github.Check{},
+		Mergeable:        github.MergeableConflicting,
+	}
+	if pr.Mergeable {
+		status.Mergeable = github.MergeableClean
+	}
+
+	var combined struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			Status    string `json:"status"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := g.poll(fmt.Sprintf("%s/commits/%s/status", g.repo(repo), pr.Head.SHA), &combined); err != nil {
+		return nil, fmt.Errorf("failed to fetch commit statuses of PR #%d: %w", number, err)
+	}
+	for _, st := range combined.Statuses {
+		state := github.CheckFailure
+		switch st.Status {
+		case "success", "warning":
+			state = github.CheckSuccess
+		case "pending":
+			state = github.CheckPending
+		}
+		status.Checks = append(status.Checks, github.Check{Name: st.Context, State: state, URL: st.TargetURL})
+	}
+
+	var reviews []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State     string `json:"state"`
+		Dismissed bool   `json:"dismissed"`
+	}
+	if err := g.poll(fmt.Sprintf("%s/pulls/%d/reviews", g.repo(repo), number), &reviews); err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews of PR #%d: %w", number, err)
+	}
+	latest := make(map[string]string)
+	for _, r := range reviews {
+		switch {
+		case r.Dismissed:
+			latest[r.User.Login] = "DISMISSED"
+		case r.State == "APPROVED":
+			latest[r.User.Login] = "APPROVED"
+		case r.State == "REQUEST_CHANGES":
+			latest[r.User.Login] = "CHANGES_REQUESTED"
+		}
+	}
+	status.ReviewDecision = github.ReviewDecision(latest)
+	return status, nil
+}
+
+// FindRelease returns the release of tag, including drafts
+func (g *Gitea) FindRelease(repo, tag string) (*github.Release, error) {
+	var releases []github.Release
+	if err := g.request("GET", g.repo(repo)+"/releases?limit=50", nil, &releases); err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	for i := range releases {
+		if releases[i].TagName == tag {
+			return &releases[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateRelease creates a release
+func (g *Gitea) CreateRelease(repo string, rel github.Release) (*github.Release, error) {
+	var created github.Release
+	if err := g.request("POST", g.repo(repo)+"/releases", rel, &created); err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+	log.Printf("Release %s created: %s\n", created.TagName, created.HTMLURL)
+	return &created, nil
+}
+
+// UpdateRelease updates a release by ID
+func (g *Gitea) UpdateRelease(repo string, rel github.Release) (*github.Release, error) {
+	var updated github.Release
+	if err := g.request("PATCH", fmt.Sprintf("%s/releases/%d", g.repo(repo), rel.ID), rel, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update release: %w", err)
+	}
+	log.Printf("Release %s updated: %s\n", updated.TagName, updated.HTMLURL)
+	return &updated, nil
+}
+
+// GetFile reads a file via the contents API; the revision marker is the blob SHA
+func (g *Gitea) GetFile(repo, path, ref string) ([]byte, string, error) {
+	var data struct {
+		SHA      string `json:"sha"`
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	u := fmt.Sprintf("%s/contents/%s?ref=%s", g.repo(repo), path, url.QueryEscape(ref))
+	if err := g.request("GET", u, nil, &data); err != nil {
+		if httpclient.IsNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if data.Encoding != "base64" {
+		return nil, "", fmt.Errorf("unsupported encoding %q for %s", data.Encoding, path)
+	}
+	content, err := base64.StdEncoding.DecodeString(data.Content)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return content, data.SHA, nil
+}
+
+// PutFile commits a file via the contents API, creating it when rev is empty
+func (g *Gitea) PutFile(repo, path, branch, message string, content []byte, rev string) (string, error) {
+	method := "POST"
+	body := map[string]string{
+		"message": message,
+		"content": base64.StdEncoding.EncodeToString(content),
+		"branch":  branch,
+	}
+	if rev != "" {
+		method = "PUT"
+		body["sha"] = rev
+	}
+	var result struct {
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+	if err := g.request(method, fmt.Sprintf("%s/contents/%s", g.repo(repo), path), body, &result); err != nil {
+		return "", fmt.Errorf("failed to commit %s: %w", path, err)
+	}
+	log.Printf("Committed %s to %s (%s)\n", path, branch, result.Commit.SHA)
+	return result.Commit.SHA, nil
+}
@@ -0,0 +1,117 @@
+package forge
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/auth"
+	"github.com/bhanurp/jfrm/internal/github"
+)
+
+// GitHub is github.com or a GitHub Enterprise Server, reached through github.CurrentHost
+type GitHub struct{}
+
+// Kind returns KindGitHub
+func (GitHub) Kind() string { return KindGitHub }
+
+// Token returns the GitHub token of the current host
+func (GitHub) Token() (string, error) {
+	return auth.Current().TokenFor(github.CurrentHost().Name)
+}
+
+// RepoURL returns the web URL of a repository
+func (GitHub) RepoURL(repo string) string {
+	return github.CurrentHost().RepoURL(repo)
+}
+
+// Releases returns the most recent releases
+func (GitHub) Releases(repo string) ([]github.ReleaseSummary, error) {
+	return github.ListReleases(repo)
+}
+
+// Tags returns the most recent tags
+func (GitHub) Tags(repo string) ([]string, error) {
+	return github.ListTags(repo)
+}
+
+// TagDate returns the date of the commit a tag dereferences to
+func (GitHub) TagDate(repo, tag string) (time.Time, error) {
+	ref, err := github.GetTagRef(repo, tag)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return github.GetCommitDate(repo, ref.CommitSHA)
+}
+
+// ReleaseDate returns when the release of tag was published
+func (GitHub) ReleaseDate(repo, tag string) (time.Time, error) {
+	return github.GetReleaseDate(repo, tag)
+}
+
+// ResolveTag resolves a tag, dereferencing annotated tag objects
+func (GitHub) ResolveTag(repo, tag string) (*github.TagRef, error) {
+	return github.GetTagRef(repo, tag)
+}
+
+// MergedChanges returns the pull requests merged since the given time
+func (GitHub) MergedChanges(repo string, since time.Time) ([]github.PullRequest, error) {
+	return github.GetAllMergedPRs(repo, since)
+}
+
+// FindOpenChange returns the open pull request from branch into base
+func (GitHub) FindOpenChange(repo, branch, base string) (int, error) {
+	return github.FindOpenPullRequest(repo, branch, base, "")
+}
+
+// CreateChange opens a pull request
+func (GitHub) CreateChange(repo string, c Change) (int, error) {
+	prID, err := github.CreatePullRequestWithContent(c.Branch, c.Base, repo, c.Title, c.Body, "")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(prID)
+}
+
+// UpdateChange replaces the title and body of a pull request
+func (GitHub) UpdateChange(repo string, number int, c Change) error {
+	return github.UpdatePullRequest(repo, number, c.Title, c.Body, "")
+}
+
+// ChangeStatus returns the state of a pull request
+func (GitHub) ChangeStatus(repo string, number int) (*ChangeStatus, error) {
+	pr, err := github.GetPullRequestState(repo, number, "")
+	if err != nil {
+		return nil, err
+	}
+	return &ChangeStatus{Number: pr.Number, State: pr.State, Merged: pr.Merged, URL: pr.HTMLURL}, nil
+}
+
+// ChangeChecks returns the commit statuses, check runs, reviews and mergeability of a pull request
+func (GitHub) ChangeChecks(repo string, number int) (*github.PullRequestStatus, error) {
+	return github.GetPullRequestStatus(repo, number, "")
+}
+
+// FindRelease returns the release of tag
+func (GitHub) FindRelease(repo, tag string) (*github.Release, error) {
+	return github.FindRelease(repo, tag, "")
+}
+
+// CreateRelease creates a release
+func (GitHub) CreateRelease(repo string, rel github.Release) (*github.Release, error) {
+	return github.CreateRelease(repo, rel, "")
+}
+
+// UpdateRelease updates a release
+func (GitHub) UpdateRelease(repo string, rel github.Release) (*github.Release, error) {
+	return github.UpdateRelease(repo, rel, "")
+}
+
+// GetFile reads a file via the contents API; the revision marker is the blob SHA
+func (GitHub) GetFile(repo, path, ref string) ([]byte, string, error) {
+	return github.GetFileContent(repo, path, ref, "")
+}
+
+// PutFile commits a file via the contents API
+func (GitHub) PutFile(repo, path, branch, message string, content []byte, rev string) (string, error) {
+	return github.PutFileContent(repo, path, branch, message, content, rev, "")
+}
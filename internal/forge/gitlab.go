@@ -0,0 +1,420 @@
+package forge
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/auth"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/httpclient"
+	"github.com/bhanurp/jfrm/internal/registry"
+)
+
+// GitLab is gitlab.com or a self-managed GitLab, using the REST API v4. GitLab has no draft
+// releases, so a draft is kept in memory and only created when it is published.
+type GitLab struct {
+	Host   string
+	APIURL string
+}
+
+// NewGitLab returns the GitLab forge on host; apiURL defaults to https://host/api/v4
+func NewGitLab(host, apiURL string) *GitLab {
+	return &GitLab{Host: host, APIURL: apiRoot(host, apiURL, "/api/v4")}
+}
+
+// Kind returns KindGitLab
+func (g *GitLab) Kind() string { return KindGitLab }
+
+// Token returns the token from GITLAB_TOKEN, the configuration or the git credential helper
+func (g *GitLab) Token() (string, error) {
+	return auth.Current().ForgeToken(g.Host, "GITLAB_TOKEN")
+}
+
+// RepoURL returns the web URL of a project
+func (g *GitLab) RepoURL(repo string) string {
+	return "https://" + g.Host + "/" + repo
+}
+
+// project returns the API root of a project, addressed by its URL-encoded path
+func (g *GitLab) project(repo string) string {
+	return g.APIURL + "/projects/" + url.PathEscape(repo)
+}
+
+// request sends an API request authenticated with a private token
+func (g *GitLab) request(method, url string, body, out interface{}) error {
+	return httpclient.DoJSON(method, url, g.authorize, body, out)
+}
+
+// authorize adds the token to a request, when there is one
+func (g *GitLab) authorize(req *http.Request) error {
+	token, err := g.Token()
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+	return nil
+}
+
+// poll sends a GET request that bypasses fresh cache entries
+func (g *GitLab) poll(url string, out interface{}) error {
+	return httpclient.DoJSON("GET", url, httpclient.NoCache(g.authorize), nil, out)
+}
+
+// gitlabRelease is a release as returned by the Releases API
+type gitlabRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ReleasedAt  time.Time `json:"released_at"`
+	Upcoming    bool      `json:"upcoming_release"`
+	Links       struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// release converts a GitLab release into the shared model
+func (r gitlabRelease) release() *github.Release {
+	return &github.Release{TagName: r.TagName, Name: r.Name, Body: r.Description, HTMLURL: r.Links.Self}
+}
+
+// Releases returns the most recent releases; GitLab has no drafts or pre-release flag, so
+// upcoming releases are reported as drafts and pre-releases are told apart by their version
+func (g *GitLab) Releases(repo string) ([]github.ReleaseSummary, error) {
+	var releases []gitlabRelease
+	if err := g.request("GET", g.project(repo)+"/releases?order_by=released_at&sort=desc&per_page=100", nil, &releases); err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	out := make([]github.ReleaseSummary, 0, len(releases))
+	for _, r := range releases {
+		out = append(out, github.ReleaseSummary{TagName: r.TagName, Draft: r.Upcoming, PublishedAt: r.ReleasedAt})
+	}
+	return out, nil
+}
+
+// Tags returns the most recently updated tags
+func (g *GitLab) Tags(repo string) ([]string, error) {
+	var tags []gitlabTag
+	if err := g.request("GET", g.project(repo)+"/repository/tags?per_page=100", nil, &tags); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
+
+// TagDate returns the date of the commit a tag points to
+func (g *GitLab) TagDate(repo, tag string) (time.Time, error) {
+	t, err := g.tag(repo, tag)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Commit.CommittedDate, nil
+}
+
+// ReleaseDate returns when the release of tag was released
+func (g *GitLab) ReleaseDate(repo, tag string) (time.Time, error) {
+	var rel gitlabRelease
+	if err := g.request("GET", g.project(repo)+"/releases/"+url.PathEscape(tag), nil, &rel); err != nil {
+		return time.Time{}, fmt.Errorf("failed to fetch release %s: %w", tag, err)
+	}
+	return rel.ReleasedAt, nil
+}
+
+// gitlabTag is a tag as returned by the Tags API; target is the tag object of an annotated
+// tag and the commit of a lightweight one
+type gitlabTag struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	Commit struct {
+		ID            string    `json:"id"`
+		CommittedDate time.Time `json:"committed_date"`
+	} `json:"commit"`
+}
+
+// tag fetches a single tag
+func (g *GitLab) tag(repo, tag string) (*gitlabTag, error) {
+	var t gitlabTag
+	if err := g.request("GET", g.project(repo)+"/repository/tags/"+url.PathEscape(tag), nil, &t); err != nil {
+		return nil, fmt.Errorf("failed to fetch tag %s: %w", tag, err)
+	}
+	if t.Commit.ID == "" {
+		return nil, fmt.Errorf("unexpected: sha is empty")
+	}
+	return &t, nil
+}
+
+// ResolveTag resolves a tag to its tag object and commit
+func (g *GitLab) ResolveTag(repo, tag string) (*github.TagRef, error) {
+	t, err := g.tag(repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	if t.Target == "" {
+		t.Target = t.Commit.ID
+	}
+	return &github.TagRef{Tag: tag, TagSHA: t.Target, CommitSHA: t.Commit.ID}, nil
+}
+
+// gitlabMergeRequest is a merge request as returned by the Merge Requests API
+type gitlabMergeRequest struct {
+	IID         int    `json:"iid"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	WebURL      string `json:"web_url"`
+	Author      struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	Labels   []string   `json:"labels"`
+	MergedAt *time.Time `json:"merged_at"`
+}
+
+// state maps the merge request state onto GitHub's model: open or closed, with merged
+// merge requests closed and flagged as merged
+func (mr gitlabMergeRequest) state() github.PullRequestState {
+	state := "closed"
+	switch mr.State {
+	case "opened", "locked":
+		state = "open"
+	}
+	return github.PullRequestState{Number: mr.IID, State: state, Merged: mr.State == "merged", HTMLURL: mr.WebURL}
+}
+
+// MergedChanges returns the merge requests merged into the base branch after since
+func (g *GitLab) MergedChanges(repo string, since time.Time) ([]github.PullRequest, error) {
+	base := registry.Current().BaseBranch(repo)
+	u := fmt.Sprintf("%s/merge_requests?state=merged&target_branch=%s&updated_after=%s&per_page=100",
+		g.project(repo), url.QueryEscape(base), url.QueryEscape(since.UTC().Format(time.RFC3339)))
+	log.Printf("Fetching merged MRs for project: %s URL used : %s\n", repo, u)
+	var mrs []gitlabMergeRequest
+	if err := g.request("GET", u, nil, &mrs); err != nil {
+		return nil, fmt.Errorf("failed to fetch MRs: %w", err)
+	}
+	var changes []github.PullRequest
+	for _, mr := range mrs {
+		if mr.MergedAt == nil || !mr.MergedAt.After(since) {
+			continue
+		}
+		changes = append(changes, github.PullRequest{
+			Number:   mr.IID,
+			Title:    mr.Title,
+			Body:     mr.Description,
+			Author:   mr.Author.Username,
+			Labels:   mr.Labels,
+			MergedAt: *mr.MergedAt,
+		})
+	}
+	return changes, nil
+}
+
+// FindOpenChange returns the open merge request from branch into base
+func (g *GitLab) FindOpenChange(repo, branch, base string) (int, error) {
+	var mrs []gitlabMergeRequest
+	u := fmt.Sprintf("%s/merge_requests?state=opened&source_branch=%s&target_branch=%s", g.project(repo), url.QueryEscape(branch), url.QueryEscape(base))
+	if err := g.request("GET", u, nil, &mrs); err != nil {
+		return 0, fmt.Errorf("failed to list merge requests: %w", err)
+	}
+	if len(mrs) == 0 {
+		return 0, nil
+	}
+	return mrs[0].IID, nil
+}
+
+// CreateChange opens a merge request
+func (g *GitLab) CreateChange(repo string, c Change) (int, error) {
+	var mr gitlabMergeRequest
+	body := map[string]string{"source_branch": c.Branch, "target_branch": c.Base, "title": c.Title, "description": c.Body}
+	if err := g.request("POST", g.project(repo)+"/merge_requests", body, &mr); err != nil {
+		return 0, fmt.Errorf("failed to create MR: %w", err)
+	}
+	log.Printf("MR created successfully: !%d\n", mr.IID)
+	return mr.IID, nil
+}
+
+// UpdateChange replaces the title and description of a merge request
+func (g *GitLab) UpdateChange(repo string, number int, c Change) error {
+	body := map[string]string{"title": c.Title, "description": c.Body}
+	if err := g.request("PUT", fmt.Sprintf("%s/merge_requests/%d", g.project(repo), number), body, nil); err != nil {
+		return fmt.Errorf("failed to update MR !%d: %w", number, err)
+	}
+	return nil
+}
+
+// ChangeStatus returns the state of a merge request; GitLab states are mapped to open,
+// closed and merged
+func (g *GitLab) ChangeStatus(repo string, number int) (*ChangeStatus, error) {
+	var mr gitlabMergeRequest
+	if err := g.request("GET", fmt.Sprintf("%s/merge_requests/%d", g.project(repo), number), nil, &mr); err != nil {
+		return nil, fmt.Errorf("failed to fetch MR !%d: %w", number, err)
+	}
+	s := mr.state()
+	return &ChangeStatus{Number: s.Number, State: s.State, Merged: s.Merged, URL: s.HTMLURL}, nil
+}
+
+// ChangeChecks returns the head pipeline, approval state and mergeability of a merge request
+func (g *GitLab) ChangeChecks(repo string, number int) (*github.PullRequestStatus, error) {
+	var mr struct {
+		gitlabMergeRequest
+		SHA                 string `json:"sha"`
+		DetailedMergeStatus string `json:"detailed_merge_status"`
+		HeadPipeline        *struct {
+			Status string `json:"status"`
+			WebURL string `json:"web_url"`
+		} `json:"head_pipeline"`
+	}
+	if err := g.poll(fmt.Sprintf("%s/merge_requests/%d", g.project(repo), number), &mr); err != nil {
+		return nil, fmt.Errorf("failed to fetch MR !%d: %w", number, err)
+	}
+	status := &github.PullRequestStatus{
+		PullRequestState: mr.state(),
+		HeadSHA:          mr.SHA,
+		Checks:           []github.Check{},
+		Mergeable:        gitlabMergeable(mr.DetailedMergeStatus),
+	}
+	if p := mr.HeadPipeline; p != nil {
+		status.Checks = append(status.Checks, github.Check{Name: "pipeline", State: gitlabPipelineState(p.Status), URL: p.WebURL})
+	}
+
+	var approvals struct {
+		Approved bool `json:"approved"`
+	}
+	if err := g.poll(fmt.Sprintf("%s/merge_requests/%d/approvals", g.project(repo), number), &approvals); err != nil {
+		return nil, fmt.Errorf("failed to fetch approvals of MR !%d: %w", number, err)
+	}
+	status.ReviewDecision = github.ReviewRequired
+	if approvals.Approved {
+		status.ReviewDecision = github.ReviewApproved
+	}
+	return status, nil
+}
+
+// gitlabPipelineState normalizes a pipeline status into a check state
+func gitlabPipelineState(status string) string {
+	switch status {
+	case "success", "skipped", "manual":
+		return github.CheckSuccess
+	case "failed", "canceled":
+		return github.CheckFailure
+	}
+	return github.CheckPending
+}
+
+// gitlabMergeable normalizes the detailed merge status of a merge request
+func gitlabMergeable(status string) string {
+	switch status {
+	case "mergeable":
+		return github.MergeableClean
+	case "conflict", "broken_status":
+		return github.MergeableConflicting
+	case "", "checking", "unchecked", "preparing", "approvals_syncing":
+		return github.MergeableUnknown
+	}
+	return github.MergeableBlocked
+}
+
+// FindRelease returns the release of tag; GitLab releases are never drafts
+func (g *GitLab) FindRelease(repo, tag string) (*github.Release, error) {
+	var rel gitlabRelease
+	if err := g.request("GET", g.project(repo)+"/releases/"+url.PathEscape(tag), nil, &rel); err != nil {
+		if httpclient.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch release %s: %w", tag, err)
+	}
+	return rel.release(), nil
+}
+
+// CreateRelease creates a release, or returns a draft unchanged without creating anything
+func (g *GitLab) CreateRelease(repo string, rel github.Release) (*github.Release, error) {
+	if rel.Draft {
+		log.Printf("GitLab has no draft releases; %s is created when it is published\n", rel.TagName)
+		return &rel, nil
+	}
+	body := map[string]string{"tag_name": rel.TagName, "ref": rel.TargetCommitish, "name": rel.Name, "description": rel.Body}
+	var created gitlabRelease
+	if err := g.request("POST", g.project(repo)+"/releases", body, &created); err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+	log.Printf("Release %s created: %s\n", created.TagName, created.Links.Self)
+	return created.release(), nil
+}
+
+// UpdateRelease publishes a draft returned by CreateRelease or updates an existing release
+func (g *GitLab) UpdateRelease(repo string, rel github.Release) (*github.Release, error) {
+	existing, err := g.FindRelease(repo, rel.TagName)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		if rel.Draft {
+			return &rel, nil
+		}
+		return g.CreateRelease(repo, rel)
+	}
+	body := map[string]string{"name": rel.Name, "description": rel.Body}
+	var updated gitlabRelease
+	if err := g.request("PUT", g.project(repo)+"/releases/"+url.PathEscape(rel.TagName), body, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update release: %w", err)
+	}
+	log.Printf("Release %s updated: %s\n", updated.TagName, updated.Links.Self)
+	return updated.release(), nil
+}
+
+// GetFile reads a file via the Repository Files API; the revision marker is the blob ID
+func (g *GitLab) GetFile(repo, path, ref string) ([]byte, string, error) {
+	var data struct {
+		BlobID   string `json:"blob_id"`
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	u := fmt.Sprintf("%s/repository/files/%s?ref=%s", g.project(repo), url.PathEscape(path), url.QueryEscape(ref))
+	if err := g.request("GET", u, nil, &data); err != nil {
+		if httpclient.IsNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if data.Encoding != "base64" {
+		return nil, "", fmt.Errorf("unsupported encoding %q for %s", data.Encoding, path)
+	}
+	content, err := base64.StdEncoding.DecodeString(data.Content)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return content, data.BlobID, nil
+}
+
+// PutFile commits a file via the Repository Files API and returns the branch head afterwards
+func (g *GitLab) PutFile(repo, path, branch, message string, content []byte, rev string) (string, error) {
+	method := "PUT"
+	if rev == "" {
+		method = "POST"
+	}
+	body := map[string]string{
+		"branch":         branch,
+		"commit_message": message,
+		"content":        base64.StdEncoding.EncodeToString(content),
+		"encoding":       "base64",
+	}
+	if err := g.request(method, fmt.Sprintf("%s/repository/files/%s", g.project(repo), url.PathEscape(path)), body, nil); err != nil {
+		return "", fmt.Errorf("failed to commit %s: %w", path, err)
+	}
+	var head struct {
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+	if err := g.request("GET", g.project(repo)+"/repository/branches/"+url.PathEscape(branch), nil, &head); err != nil {
+		return "", fmt.Errorf("failed to read %s after committing %s: %w", branch, path, err)
+	}
+	log.Printf("Committed %s to %s (%s)\n", path, branch, head.Commit.ID)
+	return head.Commit.ID, nil
+}
@@ -0,0 +1,59 @@
+package github
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/bhanurp/jfrm/internal/httpclient"
+)
+
+// GetFileContent reads a file from a branch via the contents API. It returns the
+// content and blob SHA, or nil content when the file does not exist.
+func GetFileContent(repo, path, ref, token string) ([]byte, string, error) {
+	var data struct {
+		SHA      string `json:"sha"`
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	u := fmt.Sprintf("%s/%s/contents/%s?ref=%s", reposBase(), repo, path, url.QueryEscape(ref))
+	if err := doJSONRequest("GET", u, token, nil, &data); err != nil {
+		if httpclient.IsNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if data.Encoding != "base64" {
+		return nil, "", fmt.Errorf("unsupported encoding %q for %s", data.Encoding, path)
+	}
+	content, err := base64.StdEncoding.DecodeString(data.Content)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return content, data.SHA, nil
+}
+
+// PutFileContent commits a file to a branch via the contents API and returns the new commit SHA.
+// sha must be the current blob SHA when updating an existing file and empty when creating one.
+func PutFileContent(repo, path, branch, message string, content []byte, sha, token string) (string, error) {
+	body := map[string]string{
+		"message": message,
+		"content": base64.StdEncoding.EncodeToString(content),
+		"branch":  branch,
+	}
+	if sha != "" {
+		body["sha"] = sha
+	}
+	var result struct {
+		Commit struct {
+			SHA string `json:"sha"`
+		} `json:"commit"`
+	}
+	u := fmt.Sprintf("%s/%s/contents/%s", reposBase(), repo, path)
+	if err := doJSONRequest("PUT", u, token, body, &result); err != nil {
+		return "", fmt.Errorf("failed to commit %s: %w", path, err)
+	}
+	log.Printf("Committed %s to %s (%s)\n", path, branch, result.Commit.SHA)
+	return result.Commit.SHA, nil
+}
@@ -1,52 +1,50 @@
 package github
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
-	"math"
-	"math/rand"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/bhanurp/jfrm/internal/auth"
+	"github.com/bhanurp/jfrm/internal/httpclient"
+	"github.com/bhanurp/jfrm/internal/registry"
 )
 
-// githubReposBase allows tests to override the API base (default is GitHub REST)
-var githubReposBase = "https://api.github.com/repos"
+// githubReposBase allows tests to override the repositories API root; when empty it is
+// derived from the current host
+var githubReposBase string
 
-// GetLatestReleaseVersionAndCommitSHA fetches the latest Go module version and its commit SHA
-func GetLatestReleaseVersionAndCommitSHA(module string) (string, string, time.Time, error) {
-	latestVersion, releasedTime, err := fetchLatestVersion(module)
-	if err != nil {
-		return "", "", releasedTime, fmt.Errorf("failed to fetch latest version: %w", err)
+// authorize sets the Authorization header from token, falling back to the token of the
+// process-wide provider so that reads are authenticated too
+func authorize(req *http.Request, token string) {
+	if token == "" {
+		var err error
+		if token, err = auth.Current().TokenFor(CurrentHost().Name); err != nil {
+			log.Printf("Failed to resolve a GitHub token, continuing unauthenticated: %v", err)
+		}
 	}
-
-	commitSHA, err := fetchCommitSHA(module, latestVersion)
-	if err != nil {
-		return "", "", releasedTime, fmt.Errorf("failed to fetch commit SHA for version %s: %w", latestVersion, err)
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
 	}
-
-	return latestVersion, commitSHA, releasedTime, nil
 }
 
-// fetchLatestVersion retrieves the latest tagged release version
-func fetchLatestVersion(module string) (string, time.Time, error) {
-	fmt.Printf("Fetching latest release for module: %s\n", module)
-	url := fmt.Sprintf("%s/%s/releases/latest", githubReposBase, module)
-	fmt.Println("Fetching latest release version using ", url)
-	client := &http.Client{Timeout: 15 * time.Second}
-
+// GetReleaseDate returns the publication time of the release for the given tag
+func GetReleaseDate(repo, tag string) (time.Time, error) {
+	url := fmt.Sprintf("%s/%s/releases/tags/%s", reposBase(), repo, tag)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return "", time.Time{}, err
+		return time.Time{}, err
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	authorize(req, "")
 
-	resp, err := client.Do(req)
+	resp, err := httpclient.Default().Do(req)
 	if err != nil {
-		return "", time.Time{}, err
+		return time.Time{}, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -56,95 +54,121 @@ func fetchLatestVersion(module string) (string, time.Time, error) {
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Error fetching latest version: %s\n", resp.Status)
-		return "", time.Time{}, fmt.Errorf("unexpected response code 2: %d", resp.StatusCode)
+		return time.Time{}, fmt.Errorf("failed to fetch release %s: %s", tag, resp.Status)
 	}
 
 	var data struct {
-		TagName     string    `json:"tag_name"`
 		PublishedAt time.Time `json:"published_at"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", time.Time{}, err
+		return time.Time{}, err
 	}
+	return data.PublishedAt, nil
+}
 
-	fmt.Printf("Fetched version: %s, Time: %s\n", data.TagName, data.PublishedAt)
+// TagRef is a tag resolved to the commit it marks
+type TagRef struct {
+	Tag string `json:"tag"`
+	// TagSHA is the object the tag ref points to: the tag object of an annotated tag, or
+	// the commit itself for a lightweight tag
+	TagSHA string `json:"tagSha"`
+	// CommitSHA is the commit the tag dereferences to
+	CommitSHA string `json:"commitSha"`
+}
 
-	return data.TagName, data.PublishedAt, nil
+// Annotated reports whether the tag is an annotated tag object
+func (r TagRef) Annotated() bool {
+	return r.TagSHA != "" && r.TagSHA != r.CommitSHA
 }
 
-// fetchCommitSHA retrieves the commit SHA for a given version
-func fetchCommitSHA(module, version string) (string, error) {
-	url := fmt.Sprintf("%s/%s/git/refs/tags/%s", githubReposBase, module, version)
-	fmt.Printf("Fetching commit SHA for %s\n", url)
-	client := &http.Client{Timeout: 30 * time.Second} // Increased timeout to 30 seconds
+// maxTagDepth bounds the chain of tag objects followed to reach a commit
+const maxTagDepth = 5
 
-	var resp *http.Response
-	for i := 0; i < 5; i++ { // Retry up to 5 times
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return "", fmt.Errorf("failed to create request: %w", err)
+// GetTagRef resolves a tag via the git refs API, dereferencing annotated tag objects
+// through git/tags/<sha> until it reaches the commit
+func GetTagRef(repo, tag string) (*TagRef, error) {
+	url := fmt.Sprintf("%s/%s/git/refs/tags/%s", reposBase(), repo, tag)
+	log.Printf("Fetching tag ref %s\n", url)
+	var ref struct {
+		Object gitObject `json:"object"`
+	}
+	if err := doJSONRequest("GET", url, "", nil, &ref); err != nil {
+		if httpclient.IsNotFound(err) {
+			return nil, fmt.Errorf("module or version not found: %s@%s", repo, tag)
 		}
-		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		return nil, fmt.Errorf("failed to fetch tag %s: %w", tag, err)
+	}
+	if ref.Object.SHA == "" {
+		return nil, fmt.Errorf("unexpected: sha is empty")
+	}
 
-		resp, err = client.Do(req)
-		if err != nil {
-			return "", fmt.Errorf("failed to fetch commit SHA: %w", err)
+	result := &TagRef{Tag: tag, TagSHA: ref.Object.SHA}
+	obj := ref.Object
+	for depth := 0; obj.Type == "tag"; depth++ {
+		if depth == maxTagDepth {
+			return nil, fmt.Errorf("tag %s: too many nested tag objects", tag)
 		}
-		if resp.StatusCode == http.StatusOK {
-			break
+		var tagObject struct {
+			Object gitObject `json:"object"`
 		}
-
-		if i < 4 {
-			backoff := time.Duration(math.Pow(2, float64(i))) * time.Second
-			jitter := time.Duration(rand.Intn(1000)) * time.Millisecond
-			time.Sleep(backoff + jitter)
+		if err := doJSONRequest("GET", fmt.Sprintf("%s/%s/git/tags/%s", reposBase(), repo, obj.SHA), "", nil, &tagObject); err != nil {
+			return nil, fmt.Errorf("failed to dereference tag %s: %w", tag, err)
 		}
+		obj = tagObject.Object
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			log.Printf("Error closing response body: %v", err)
-		}
-	}(resp.Body)
-	if resp.StatusCode == http.StatusNotFound {
-		return "", fmt.Errorf("module or version not found: %s@%s", module, version)
+	if obj.Type != "" && obj.Type != "commit" {
+		return nil, fmt.Errorf("tag %s points to a %s, not a commit", tag, obj.Type)
 	}
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Error fetching latest version: %s\n", resp.Body)
-		return "", fmt.Errorf("unexpected response code 1: %d", resp.StatusCode)
+	result.CommitSHA = obj.SHA
+	if result.Annotated() {
+		log.Printf("Resolved annotated tag %s (tag object %s) to commit %s\n", tag, result.TagSHA, result.CommitSHA)
 	}
+	return result, nil
+}
 
-	var data struct {
-		Ref    string `json:"ref"`
-		URL    string `json:"url"`
-		Object struct {
-			SHA  string `json:"sha"`
-			Type string `json:"type"`
-		} `json:"object"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
-	}
-	fmt.Printf("Received data for commit sha: %v\n", data)
+// gitObject is the target of a ref or tag object
+type gitObject struct {
+	SHA  string `json:"sha"`
+	Type string `json:"type"`
+}
 
-	if data.Object.SHA == "" {
-		return "", fmt.Errorf("unexpected: sha is empty")
-	}
+// PullRequest describes a merged pull request relevant to release planning
+type PullRequest struct {
+	Number   int       `json:"number"`
+	Title    string    `json:"title"`
+	Body     string    `json:"-"`
+	Author   string    `json:"author"`
+	Labels   []string  `json:"labels"`
+	MergedAt time.Time `json:"mergedAt"`
+}
+
+// String renders the pull request in the one-line form used by logs and reports
+func (pr PullRequest) String() string {
+	return fmt.Sprintf("PR #%d, %s, %s, %s, %s", pr.Number, pr.Title, pr.Author, strings.Join(pr.Labels, ", "), pr.MergedAt)
+}
 
-	return data.Object.SHA, nil
+// HasLabel reports whether the pull request carries the given label (case-insensitive)
+func (pr PullRequest) HasLabel(name string) bool {
+	for _, label := range pr.Labels {
+		if strings.EqualFold(label, name) {
+			return true
+		}
+	}
+	return false
 }
 
 // GetAllMergedPRs fetches all merged PRs since the last release
-func GetAllMergedPRs(repo string, lastReleaseDate time.Time) ([]string, error) {
-	base := "dev"
-	if repo == "jfrog/jfrog-cli-artifactory" {
-		base = "main"
-	}
-	url := fmt.Sprintf("%s/%s/pulls?state=closed&base=%s", githubReposBase, repo, base)
-	fmt.Printf("Fetching all closed PRs for repo: %s URL used : %s\n", repo, url)
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(url)
+func GetAllMergedPRs(repo string, lastReleaseDate time.Time) ([]PullRequest, error) {
+	base := registry.Current().BaseBranch(repo)
+	url := fmt.Sprintf("%s/%s/pulls?state=closed&base=%s", reposBase(), repo, base)
+	log.Printf("Fetching all closed PRs for repo: %s URL used : %s\n", repo, url)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	authorize(req, "")
+	resp, err := httpclient.Default().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -162,6 +186,7 @@ func GetAllMergedPRs(repo string, lastReleaseDate time.Time) ([]string, error) {
 	var prs []struct {
 		Number int    `json:"number"`
 		Title  string `json:"title"`
+		Body   string `json:"body"`
 		User   struct {
 			Login string `json:"login"`
 		} `json:"user"`
@@ -175,16 +200,21 @@ func GetAllMergedPRs(repo string, lastReleaseDate time.Time) ([]string, error) {
 		return nil, err
 	}
 
-	var prList []string
+	var prList []PullRequest
 	for _, pr := range prs {
-		if pr.ClosedAt.After(lastReleaseDate) {
-			var tags []string
+		if pr.ClosedAt != nil && pr.ClosedAt.After(lastReleaseDate) && pr.MergedAt != nil {
+			var labels []string
 			for _, label := range pr.Labels {
-				tags = append(tags, label.Name)
-			}
-			if pr.MergedAt != nil {
-				prList = append(prList, fmt.Sprintf("PR #%d, %s, %s, %s, %s", pr.Number, pr.Title, pr.User.Login, strings.Join(tags, ", "), pr.MergedAt))
+				labels = append(labels, label.Name)
 			}
+			prList = append(prList, PullRequest{
+				Number:   pr.Number,
+				Title:    pr.Title,
+				Body:     pr.Body,
+				Author:   pr.User.Login,
+				Labels:   labels,
+				MergedAt: *pr.MergedAt,
+			})
 		}
 	}
 
@@ -193,63 +223,76 @@ func GetAllMergedPRs(repo string, lastReleaseDate time.Time) ([]string, error) {
 
 // CreatePullRequest creates a pull request
 func CreatePullRequest(branch, base, repo, token string) (string, error) {
+	return CreatePullRequestWithContent(branch, base, repo, "Update dependencies", "This PR updates Go dependencies to the latest versions.", token)
+}
+
+// CreatePullRequestWithContent creates a pull request with the given title and body
+func CreatePullRequestWithContent(branch, base, repo, title, body, token string) (string, error) {
 	prBody := map[string]string{
-		"title": "Update dependencies",
+		"title": title,
 		"head":  branch,
 		"base":  base,
-		"body":  "This PR updates Go dependencies to the latest versions.",
+		"body":  body,
 	}
-	jsonBody, _ := json.Marshal(prBody)
-	req, _ := http.NewRequest("POST", fmt.Sprintf("%s/%s/pulls", githubReposBase, repo), bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "token "+token)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return "", err
+	var result struct {
+		Number int `json:"number"`
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			log.Printf("Error closing response body: %v", err)
-		}
-	}(resp.Body)
-	if resp.StatusCode >= 300 {
-		return "", fmt.Errorf("failed to create PR: %s", resp.Status)
+	if err := doJSONRequest("POST", fmt.Sprintf("%s/%s/pulls", reposBase(), repo), token, prBody, &result); err != nil {
+		return "", fmt.Errorf("failed to create PR: %w", err)
 	}
-	var result map[string]interface{}
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
-		return "", err
+	log.Printf("PR created successfully: #%d\n", result.Number)
+	return fmt.Sprintf("%d", result.Number), nil
+}
+
+// FindOpenPullRequest returns the number of the open pull request from branch into base, or 0 when there is none
+func FindOpenPullRequest(repo, branch, base, token string) (int, error) {
+	owner, _, _ := strings.Cut(repo, "/")
+	var prs []struct {
+		Number int `json:"number"`
+	}
+	url := fmt.Sprintf("%s/%s/pulls?state=open&head=%s:%s&base=%s", reposBase(), repo, owner, branch, base)
+	if err := doJSONRequest("GET", url, token, nil, &prs); err != nil {
+		return 0, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	if len(prs) == 0 {
+		return 0, nil
 	}
-	prID, _ := result["number"].(float64)
-	log.Printf("PR created successfully: #%d\n", int(prID))
-	return fmt.Sprintf("%d", int(prID)), nil
+	return prs[0].Number, nil
 }
 
-// GetPullRequestStatus fetches the status of a pull request
-func GetPullRequestStatus(prID, repo, token string) error {
-	req, _ := http.NewRequest("GET", fmt.Sprintf("%s/%s/pulls/%s", githubReposBase, repo, prID), nil)
-	req.Header.Set("Authorization", "token "+token)
-	req.Header.Set("Content-Type", "application/json")
+// PullRequestState is the state of a single pull request
+type PullRequestState struct {
+	Number  int    `json:"number"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+	HTMLURL string `json:"html_url"`
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
+// GetPullRequestState fetches the state of a pull request
+func GetPullRequestState(repo string, number int, token string) (*PullRequestState, error) {
+	var data PullRequestState
+	url := fmt.Sprintf("%s/%s/pulls/%d", reposBase(), repo, number)
+	if err := doJSONRequest("GET", url, token, nil, &data); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR #%d: %w", number, err)
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			log.Printf("Error closing response body: %v", err)
-		}
-	}(resp.Body)
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("failed to fetch PR status: %s", resp.Status)
+	return &data, nil
+}
+
+// UpdatePullRequest replaces the title and body of a pull request
+func UpdatePullRequest(repo string, number int, title, body, token string) error {
+	url := fmt.Sprintf("%s/%s/pulls/%d", reposBase(), repo, number)
+	var result struct{}
+	if err := doJSONRequest("PATCH", url, token, map[string]string{"title": title, "body": body}, &result); err != nil {
+		return fmt.Errorf("failed to update PR #%d: %w", number, err)
 	}
-	var result map[string]interface{}
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	return nil
+}
+
+// IsPullRequestMerged reports whether a pull request has been merged
+func IsPullRequestMerged(repo string, number int, token string) (bool, error) {
+	pr, err := GetPullRequestState(repo, number, token)
 	if err != nil {
-		return err
+		return false, err
 	}
-	log.Printf("PR Status: %s\n", result["state"].(string))
-	return nil
+	return pr.Merged, nil
 }
@@ -2,28 +2,27 @@ package github
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
-	"time"
 )
 
-func TestGetLatestReleaseVersionAndCommitSHA(t *testing.T) {
+func TestGetTagRefAnnotated(t *testing.T) {
 	mux := http.NewServeMux()
-	// latest release endpoint
-	mux.HandleFunc("/owner/repo/releases/latest", func(w http.ResponseWriter, r *http.Request) {
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{
-			"tag_name":     "v1.2.3",
-			"published_at": time.Now().Format(time.RFC3339),
-		})
-	})
-	// tag ref endpoint
 	mux.HandleFunc("/owner/repo/git/refs/tags/v1.2.3", func(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]interface{}{
 			"ref":    "refs/tags/v1.2.3",
 			"object": map[string]string{"sha": "abc123", "type": "tag"},
 		})
 	})
+	// annotated tag object endpoint
+	mux.HandleFunc("/owner/repo/git/tags/abc123", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"sha":    "abc123",
+			"object": map[string]string{"sha": "def456", "type": "commit"},
+		})
+	})
 
 	ts := httptest.NewServer(mux)
 	defer ts.Close()
@@ -32,12 +31,182 @@ func TestGetLatestReleaseVersionAndCommitSHA(t *testing.T) {
 	githubReposBase = ts.URL
 	defer func() { githubReposBase = oldBase }()
 
-	tag, sha, tms, err := GetLatestReleaseVersionAndCommitSHA("owner/repo")
+	ref, err := GetTagRef("owner/repo", "v1.2.3")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if tag != "v1.2.3" || sha != "abc123" || tms.IsZero() {
-		t.Fatalf("unexpected result tag=%s sha=%s time=%v", tag, sha, tms)
+	if ref.TagSHA != "abc123" || ref.CommitSHA != "def456" || !ref.Annotated() {
+		t.Fatalf("unexpected tag ref %+v", ref)
+	}
+}
+
+func TestFindAndUpdateRelease(t *testing.T) {
+	var patched Release
+	mux := http.NewServeMux()
+	mux.HandleFunc("/owner/repo/releases/tags/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/owner/repo/releases/tags/v1.0.0" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Release{ID: 1, TagName: "v1.0.0"})
+	})
+	mux.HandleFunc("/owner/repo/releases", func(w http.ResponseWriter, r *http.Request) {
+		// A full first page of published releases pushes the draft onto the second page
+		var releases []Release
+		if r.URL.Query().Get("page") == "1" {
+			for i := 0; i < releasesPerPage; i++ {
+				releases = append(releases, Release{ID: int64(100 + i), TagName: fmt.Sprintf("v0.%d.0", i)})
+			}
+		} else {
+			releases = []Release{{ID: 2, TagName: "v1.1.0", Draft: true}}
+		}
+		_ = json.NewEncoder(w).Encode(releases)
+	})
+	mux.HandleFunc("/owner/repo/releases/2", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&patched)
+		_ = json.NewEncoder(w).Encode(patched)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	oldBase := githubReposBase
+	githubReposBase = ts.URL
+	defer func() { githubReposBase = oldBase }()
+
+	rel, err := FindRelease("owner/repo", "v1.1.0", "secret")
+	if err != nil || rel == nil || rel.ID != 2 || !rel.Draft {
+		t.Fatalf("expected draft release 2, got %+v (err %v)", rel, err)
+	}
+	if published, err := FindRelease("owner/repo", "v1.0.0", "secret"); err != nil || published == nil || published.ID != 1 {
+		t.Fatalf("expected published release 1, got %+v (err %v)", published, err)
+	}
+	if missing, err := FindRelease("owner/repo", "v9.9.9", "secret"); err != nil || missing != nil {
+		t.Fatalf("expected no release, got %+v (err %v)", missing, err)
+	}
+
+	rel.Draft = false
+	rel.Body = "notes"
+	if _, err := UpdateRelease("owner/repo", *rel, "secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patched.Draft || patched.Body != "notes" {
+		t.Fatalf("unexpected patch payload: %+v", patched)
+	}
+}
+
+func TestGetTagRefLightweight(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/owner/repo/git/refs/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"object": map[string]string{"sha": "c0ffee", "type": "commit"},
+		})
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	oldBase := githubReposBase
+	githubReposBase = ts.URL
+	defer func() { githubReposBase = oldBase }()
+
+	ref, err := GetTagRef("owner/repo", "v1.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref.TagSHA != "c0ffee" || ref.CommitSHA != "c0ffee" || ref.Annotated() {
+		t.Fatalf("unexpected tag ref %+v", ref)
+	}
+	if _, err := GetTagRef("owner/repo", "v9.9.9"); err == nil {
+		t.Fatalf("expected error for a missing tag")
 	}
 }
 
+func TestHostFor(t *testing.T) {
+	if h := HostFor(""); h.APIURL != "https://api.github.com" || h.UploadURL != "https://uploads.github.com" || h.WebURL != "https://github.com" {
+		t.Fatalf("unexpected github.com endpoints: %+v", h)
+	}
+	h := HostFor("GHE.example.com").With(Host{UploadURL: "https://uploads.ghe.example.com/"})
+	want := Host{Name: "ghe.example.com", APIURL: "https://ghe.example.com/api/v3", UploadURL: "https://uploads.ghe.example.com", WebURL: "https://ghe.example.com"}
+	if h != want {
+		t.Fatalf("expected %+v, got %+v", want, h)
+	}
+	if got := h.CloneURL("jfrog/jfrog-cli"); got != "https://ghe.example.com/jfrog/jfrog-cli.git" {
+		t.Fatalf("unexpected clone URL %s", got)
+	}
+}
+
+func TestRequestsUseCurrentHost(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/owner/repo/releases/tags/v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"published_at": "2025-01-02T03:04:05Z"})
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	old := CurrentHost()
+	SetCurrentHost(HostFor("ghe.example.com").With(Host{APIURL: ts.URL + "/api/v3"}))
+	defer SetCurrentHost(old)
+
+	published, err := GetReleaseDate("owner/repo", "v1.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published.Year() != 2025 {
+		t.Fatalf("unexpected release date %v", published)
+	}
+}
+
+func TestGetPullRequestStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/owner/repo/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"number": 7, "state": "open", "head": map[string]string{"sha": "abc"},
+			"mergeable": true, "mergeable_state": "blocked",
+		})
+	})
+	mux.HandleFunc("/owner/repo/commits/abc/status", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"statuses": []map[string]string{{"context": "ci/jenkins", "state": "success"}},
+		})
+	})
+	mux.HandleFunc("/owner/repo/commits/abc/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"check_runs": []map[string]string{
+				{"name": "lint", "status": "completed", "conclusion": "skipped"},
+				{"name": "test", "status": "completed", "conclusion": "failure"},
+			},
+		})
+	})
+	mux.HandleFunc("/owner/repo/pulls/7/reviews", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"user": map[string]string{"login": "a"}, "state": "CHANGES_REQUESTED"},
+			{"user": map[string]string{"login": "b"}, "state": "APPROVED"},
+			{"user": map[string]string{"login": "a"}, "state": "APPROVED"},
+		})
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	oldBase := githubReposBase
+	githubReposBase = ts.URL
+	defer func() { githubReposBase = oldBase }()
+
+	status, err := GetPullRequestStatus("owner/repo", 7, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(status.Checks) != 3 || status.ChecksState() != CheckFailure {
+		t.Fatalf("expected a failed check, got %+v", status.Checks)
+	}
+	if status.ReviewDecision != ReviewApproved || status.Mergeable != MergeableBlocked || !status.Done() {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if got, want := status.Summary(), "PR #7 open: checks 2/3 passed, 0 pending, 1 failed (test); approved; blocked"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/bhanurp/jfrm/internal/auth"
+)
+
+// Host holds the endpoints of a GitHub installation: github.com or a GitHub Enterprise Server
+type Host struct {
+	// Name is the host name used in remote URLs and for looking up tokens, e.g. "github.com"
+	Name string `json:"name"`
+	// APIURL is the REST API root, e.g. "https://api.github.com" or "https://ghe.example.com/api/v3"
+	APIURL string `json:"apiUrl"`
+	// UploadURL is the root for release asset uploads
+	UploadURL string `json:"uploadUrl"`
+	// WebURL is the root of repository pages and clone URLs, e.g. "https://github.com"
+	WebURL string `json:"webUrl"`
+}
+
+// HostFor returns the default endpoints of a host: those of github.com for "github.com" or an
+// empty name, and the GitHub Enterprise Server layout (https://<name>/api/v3) otherwise
+func HostFor(name string) Host {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == auth.DefaultHost || name == "api.github.com" || name == "www.github.com" {
+		return Host{
+			Name:      auth.DefaultHost,
+			APIURL:    "https://api.github.com",
+			UploadURL: "https://uploads.github.com",
+			WebURL:    "https://github.com",
+		}
+	}
+	return Host{
+		Name:      name,
+		APIURL:    "https://" + name + "/api/v3",
+		UploadURL: "https://" + name + "/api/uploads",
+		WebURL:    "https://" + name,
+	}
+}
+
+// With returns h with the non-empty endpoints of o replacing its own
+func (h Host) With(o Host) Host {
+	if o.APIURL != "" {
+		h.APIURL = strings.TrimSuffix(o.APIURL, "/")
+	}
+	if o.UploadURL != "" {
+		h.UploadURL = strings.TrimSuffix(o.UploadURL, "/")
+	}
+	if o.WebURL != "" {
+		h.WebURL = strings.TrimSuffix(o.WebURL, "/")
+	}
+	return h
+}
+
+// RepoURL returns the web URL of a repository, e.g. "https://github.com/owner/repo"
+func (h Host) RepoURL(repo string) string {
+	return h.WebURL + "/" + repo
+}
+
+// CloneURL returns the HTTPS clone URL of a repository
+func (h Host) CloneURL(repo string) string {
+	return h.RepoURL(repo) + ".git"
+}
+
+var (
+	hostMu  sync.RWMutex
+	current = HostFor(auth.DefaultHost)
+)
+
+// CurrentHost returns the GitHub host all API requests of this process go to
+func CurrentHost() Host {
+	hostMu.RLock()
+	defer hostMu.RUnlock()
+	return current
+}
+
+// SetCurrentHost replaces the GitHub host used by all API requests in this process
+func SetCurrentHost(h Host) {
+	hostMu.Lock()
+	defer hostMu.Unlock()
+	current = h
+}
+
+// reposBase returns the root of the repository endpoints
+func reposBase() string {
+	if githubReposBase != "" {
+		return githubReposBase
+	}
+	return CurrentHost().APIURL + "/repos"
+}
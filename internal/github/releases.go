@@ -0,0 +1,144 @@
+package github
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/httpclient"
+)
+
+// Release is a GitHub release as returned by the Releases API
+type Release struct {
+	ID              int64  `json:"id,omitempty"`
+	TagName         string `json:"tag_name"`
+	TargetCommitish string `json:"target_commitish,omitempty"`
+	Name            string `json:"name"`
+	Body            string `json:"body"`
+	Draft           bool   `json:"draft"`
+	Prerelease      bool   `json:"prerelease"`
+	HTMLURL         string `json:"html_url,omitempty"`
+}
+
+// releasesPerPage is the page size used when listing releases
+const releasesPerPage = 100
+
+// FindRelease returns the release (including drafts) for a tag, or nil when none exists.
+// Published releases are looked up by tag; drafts are not, so the release list is searched
+// page by page for them.
+func FindRelease(repo, tag, token string) (*Release, error) {
+	var rel Release
+	url := fmt.Sprintf("%s/%s/releases/tags/%s", reposBase(), repo, tag)
+	err := doJSONRequest("GET", url, token, nil, &rel)
+	if err == nil {
+		return &rel, nil
+	}
+	if !httpclient.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to fetch release %s: %w", tag, err)
+	}
+
+	for page := 1; ; page++ {
+		var releases []Release
+		url := fmt.Sprintf("%s/%s/releases?per_page=%d&page=%d", reposBase(), repo, releasesPerPage, page)
+		if err := doJSONRequest("GET", url, token, nil, &releases); err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+		for _, r := range releases {
+			if r.Draft && r.TagName == tag {
+				rel := r
+				return &rel, nil
+			}
+		}
+		if len(releases) < releasesPerPage {
+			return nil, nil
+		}
+	}
+}
+
+// ReleaseSummary is a release as listed by the Releases API
+type ReleaseSummary struct {
+	TagName     string    `json:"tag_name"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// ListReleases returns the most recent releases of repo, including drafts and pre-releases
+func ListReleases(repo string) ([]ReleaseSummary, error) {
+	var releases []ReleaseSummary
+	url := fmt.Sprintf("%s/%s/releases?per_page=100", reposBase(), repo)
+	if err := doJSONRequest("GET", url, "", nil, &releases); err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+	return releases, nil
+}
+
+// ListTags returns the names of the most recent tags of repo
+func ListTags(repo string) ([]string, error) {
+	var tags []struct {
+		Name string `json:"name"`
+	}
+	url := fmt.Sprintf("%s/%s/tags?per_page=100", reposBase(), repo)
+	if err := doJSONRequest("GET", url, "", nil, &tags); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
+
+// GetCommitDate returns the committer date of a commit
+func GetCommitDate(repo, sha string) (time.Time, error) {
+	var commit struct {
+		Commit struct {
+			Committer struct {
+				Date time.Time `json:"date"`
+			} `json:"committer"`
+		} `json:"commit"`
+	}
+	url := fmt.Sprintf("%s/%s/commits/%s", reposBase(), repo, sha)
+	if err := doJSONRequest("GET", url, "", nil, &commit); err != nil {
+		return time.Time{}, fmt.Errorf("failed to fetch commit %s: %w", sha, err)
+	}
+	return commit.Commit.Committer.Date, nil
+}
+
+// CreateRelease creates a release; the tag is created from TargetCommitish when it does not exist
+func CreateRelease(repo string, rel Release, token string) (*Release, error) {
+	var created Release
+	url := fmt.Sprintf("%s/%s/releases", reposBase(), repo)
+	if err := doJSONRequest("POST", url, token, rel, &created); err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+	log.Printf("Release %s created: %s\n", created.TagName, created.HTMLURL)
+	return &created, nil
+}
+
+// UpdateRelease updates the name, body, draft and pre-release state of an existing release
+func UpdateRelease(repo string, rel Release, token string) (*Release, error) {
+	var updated Release
+	url := fmt.Sprintf("%s/%s/releases/%d", reposBase(), repo, rel.ID)
+	if err := doJSONRequest("PATCH", url, token, rel, &updated); err != nil {
+		return nil, fmt.Errorf("failed to update release: %w", err)
+	}
+	log.Printf("Release %s updated: %s\n", updated.TagName, updated.HTMLURL)
+	return &updated, nil
+}
+
+// doJSONRequest sends an API request authenticated with token, or the provider's token
+// when empty, and decodes the JSON response into out
+func doJSONRequest(method, url, token string, body interface{}, out interface{}) error {
+	return httpclient.DoJSON(method, url, apiAuthorizer(token), body, out)
+}
+
+// apiAuthorizer returns the callback adding the API media type and token to a request
+func apiAuthorizer(token string) func(*http.Request) error {
+	return func(req *http.Request) error {
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
+		authorize(req, token)
+		return nil
+	}
+}
@@ -0,0 +1,220 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bhanurp/jfrm/internal/httpclient"
+)
+
+// Check states, normalized across commit statuses, check runs and other forges' pipelines
+const (
+	CheckPending = "pending"
+	CheckSuccess = "success"
+	CheckFailure = "failure"
+)
+
+// Review decisions
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewRequired         = "review_required"
+)
+
+// Mergeable states
+const (
+	MergeableClean       = "mergeable"
+	MergeableConflicting = "conflicting"
+	MergeableBlocked     = "blocked"
+	MergeableUnknown     = "unknown"
+)
+
+// Check is a commit status or check run reported on the head commit of a pull request
+type Check struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	URL   string `json:"url,omitempty"`
+}
+
+// PullRequestStatus is the CI, review and merge state of a pull request
+type PullRequestStatus struct {
+	PullRequestState
+	HeadSHA        string  `json:"headSha"`
+	Checks         []Check `json:"checks"`
+	ReviewDecision string  `json:"reviewDecision"`
+	Mergeable      string  `json:"mergeable"`
+}
+
+// ChecksState summarizes the checks: pending while any check runs, failure when any check
+// failed, success otherwise (including when there are no checks)
+func (s PullRequestStatus) ChecksState() string {
+	state := CheckSuccess
+	for _, c := range s.Checks {
+		switch c.State {
+		case CheckFailure:
+			return CheckFailure
+		case CheckPending:
+			state = CheckPending
+		}
+	}
+	return state
+}
+
+// Done reports whether the pull request has settled: it is closed, or its checks finished
+// and its mergeability is known
+func (s PullRequestStatus) Done() bool {
+	return s.State == "closed" || s.Merged || (s.ChecksState() != CheckPending && s.Mergeable != MergeableUnknown)
+}
+
+// Summary describes the status on one line
+func (s PullRequestStatus) Summary() string {
+	state := s.State
+	if s.Merged {
+		state = "merged"
+	}
+	counts := map[string]int{}
+	var failed []string
+	for _, c := range s.Checks {
+		counts[c.State]++
+		if c.State == CheckFailure {
+			failed = append(failed, c.Name)
+		}
+	}
+	checks := "no checks"
+	if len(s.Checks) > 0 {
+		checks = fmt.Sprintf("checks %d/%d passed, %d pending, %d failed", counts[CheckSuccess], len(s.Checks), counts[CheckPending], counts[CheckFailure])
+		if len(failed) > 0 {
+			checks += " (" + strings.Join(failed, ", ") + ")"
+		}
+	}
+	review := s.ReviewDecision
+	if review == "" {
+		review = "no reviews"
+	}
+	return fmt.Sprintf("PR #%d %s: %s; %s; %s", s.Number, state, checks, strings.ReplaceAll(review, "_", " "), s.Mergeable)
+}
+
+// GetPullRequestStatus fetches the state, checks, reviews and mergeability of a pull request,
+// bypassing fresh cache entries so that polling sees every change
+func GetPullRequestStatus(repo string, number int, token string) (*PullRequestStatus, error) {
+	get := func(url string, out interface{}) error {
+		return httpclient.DoJSON("GET", url, httpclient.NoCache(apiAuthorizer(token)), nil, out)
+	}
+	var pr struct {
+		PullRequestState
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+		Mergeable      *bool  `json:"mergeable"`
+		MergeableState string `json:"mergeable_state"`
+	}
+	if err := get(fmt.Sprintf("%s/%s/pulls/%d", reposBase(), repo, number), &pr); err != nil {
+		return nil, fmt.Errorf("failed to fetch PR #%d: %w", number, err)
+	}
+	status := &PullRequestStatus{
+		PullRequestState: pr.PullRequestState,
+		HeadSHA:          pr.Head.SHA,
+		Checks:           []Check{},
+		Mergeable:        mergeableState(pr.Mergeable, pr.MergeableState),
+	}
+
+	var combined struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			State     string `json:"state"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := get(fmt.Sprintf("%s/%s/commits/%s/status", reposBase(), repo, pr.Head.SHA), &combined); err != nil {
+		return nil, fmt.Errorf("failed to fetch commit statuses of PR #%d: %w", number, err)
+	}
+	for _, st := range combined.Statuses {
+		status.Checks = append(status.Checks, Check{Name: st.Context, State: commitStatusState(st.State), URL: st.TargetURL})
+	}
+
+	var runs struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+		} `json:"check_runs"`
+	}
+	if err := get(fmt.Sprintf("%s/%s/commits/%s/check-runs?per_page=100", reposBase(), repo, pr.Head.SHA), &runs); err != nil {
+		return nil, fmt.Errorf("failed to fetch check runs of PR #%d: %w", number, err)
+	}
+	for _, run := range runs.CheckRuns {
+		status.Checks = append(status.Checks, Check{Name: run.Name, State: checkRunState(run.Status, run.Conclusion), URL: run.HTMLURL})
+	}
+
+	var reviews []struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		State string `json:"state"`
+	}
+	if err := get(fmt.Sprintf("%s/%s/pulls/%d/reviews?per_page=100", reposBase(), repo, number), &reviews); err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews of PR #%d: %w", number, err)
+	}
+	latest := make(map[string]string)
+	for _, r := range reviews {
+		if r.State == "APPROVED" || r.State == "CHANGES_REQUESTED" || r.State == "DISMISSED" {
+			latest[r.User.Login] = r.State
+		}
+	}
+	status.ReviewDecision = ReviewDecision(latest)
+	return status, nil
+}
+
+// commitStatusState normalizes the state of a commit status
+func commitStatusState(state string) string {
+	switch state {
+	case "success":
+		return CheckSuccess
+	case "pending":
+		return CheckPending
+	}
+	return CheckFailure
+}
+
+// checkRunState normalizes the status and conclusion of a check run; neutral and skipped
+// runs do not block a merge and count as successful
+func checkRunState(status, conclusion string) string {
+	if status != "completed" {
+		return CheckPending
+	}
+	switch conclusion {
+	case "success", "neutral", "skipped":
+		return CheckSuccess
+	}
+	return CheckFailure
+}
+
+// ReviewDecision derives the review decision from the latest review state (APPROVED,
+// CHANGES_REQUESTED or DISMISSED) of each reviewer
+func ReviewDecision(latest map[string]string) string {
+	decision := ReviewRequired
+	for _, state := range latest {
+		switch state {
+		case "CHANGES_REQUESTED":
+			return ReviewChangesRequested
+		case "APPROVED":
+			decision = ReviewApproved
+		}
+	}
+	return decision
+}
+
+// mergeableState normalizes GitHub's mergeable flag and mergeable_state, which stay unknown
+// while GitHub computes them in the background
+func mergeableState(mergeable *bool, state string) string {
+	switch {
+	case mergeable == nil || state == "unknown":
+		return MergeableUnknown
+	case !*mergeable || state == "dirty":
+		return MergeableConflicting
+	case state == "blocked" || state == "behind" || state == "draft":
+		return MergeableBlocked
+	}
+	return MergeableClean
+}
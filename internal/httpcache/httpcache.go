@@ -0,0 +1,282 @@
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Header is set on responses served from the cache, with the value Hit or Revalidated
+const Header = "X-Jfrm-Cache"
+
+// Values of Header
+const (
+	Hit         = "hit"
+	Revalidated = "revalidated"
+	// Stale marks an expired entry served without revalidation in offline mode
+	Stale = "stale"
+)
+
+// ErrOffline is returned in offline mode for requests that have no cached response
+var ErrOffline = errors.New("offline: no cached response")
+
+// DefaultTTL applies to URLs no TTL rule matches: they are revalidated on every request, so
+// GitHub listings and states are never served stale (304 responses are free of rate limit)
+const DefaultTTL = 0
+
+// TTLRule gives responses whose URL contains Match a freshness lifetime
+type TTLRule struct {
+	Match string
+	TTL   time.Duration
+}
+
+// DefaultTTLs are checked in order; only the immutable module version files of the proxy
+// are served without revalidation
+var DefaultTTLs = []TTLRule{
+	{Match: "/@v/list", TTL: 0},
+	{Match: "/@v/", TTL: 30 * 24 * time.Hour},
+}
+
+// Dir returns the cache directory: $XDG_CACHE_HOME/jfrm (or the platform equivalent)
+func Dir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "jfrm"), nil
+}
+
+// Clean removes everything stored in dir
+func Clean(dir string) error {
+	return os.RemoveAll(dir)
+}
+
+// Transport is an http.RoundTripper that stores successful GET responses on disk, keyed
+// by URL, serves them while fresh and revalidates them with If-None-Match / If-Modified-Since.
+// Requests sent with Cache-Control: no-cache skip fresh entries and are always revalidated.
+// A successful write to a repository drops the cached responses of that repository.
+type Transport struct {
+	Dir  string
+	Base http.RoundTripper
+	TTLs []TTLRule
+	// Offline serves cached responses regardless of their age and never contacts the server
+	Offline bool
+	now     func() time.Time
+}
+
+// New creates a caching transport storing responses in dir and sending requests through base
+func New(dir string, base http.RoundTripper) *Transport {
+	return &Transport{Dir: dir, Base: base, TTLs: DefaultTTLs, now: time.Now}
+}
+
+// entry is the on-disk form of a cached response
+type entry struct {
+	URL      string    `json:"url"`
+	StoredAt time.Time `json:"storedAt"`
+	// Response is the HTTP/1.1 wire form of the response
+	Response []byte `json:"response"`
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		if t.Offline {
+			return nil, fmt.Errorf("%w: %s %s", ErrOffline, req.Method, url)
+		}
+		resp, err := t.Base.RoundTrip(req)
+		if err == nil && isWrite(req.Method) && resp.StatusCode < 300 {
+			t.invalidateRepo(url)
+		}
+		return resp, err
+	}
+	cached, storedAt := t.load(url, req)
+	// A request with Cache-Control: no-cache is always revalidated, even when the entry is fresh
+	noCache := strings.Contains(req.Header.Get("Cache-Control"), "no-cache")
+	fresh := cached != nil && !noCache && t.now().Sub(storedAt) < t.ttl(url)
+	if fresh {
+		cached.Header.Set(Header, Hit)
+		return cached, nil
+	}
+	if t.Offline {
+		if cached == nil {
+			return nil, fmt.Errorf("%w for %s", ErrOffline, url)
+		}
+		cached.Header.Set(Header, Stale)
+		return cached, nil
+	}
+
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := cached.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_ = resp.Body.Close()
+		t.touch(url)
+		cached.Header.Set(Header, Revalidated)
+		return cached, nil
+	}
+	if resp.StatusCode == http.StatusOK {
+		return t.store(url, resp)
+	}
+	return resp, nil
+}
+
+// isWrite reports whether method changes server state
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// invalidateRepo drops the cached responses of the repository a write went to, so listings
+// read right after creating a release or PR never miss it
+func (t *Transport) invalidateRepo(url string) {
+	root := repoRoot(url)
+	if root == "" {
+		return
+	}
+	_ = os.RemoveAll(t.repoDir(root))
+}
+
+// repoRoot returns url up to the repository of a /repos/<owner>/<repo> API path, or "" when
+// url addresses no repository
+func repoRoot(url string) string {
+	i := strings.Index(url, "/repos/")
+	if i < 0 {
+		return ""
+	}
+	rest := url[i+len("/repos/"):]
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+	repo := strings.SplitN(parts[1], "?", 2)[0]
+	return url[:i] + "/repos/" + parts[0] + "/" + repo
+}
+
+// ttl returns the freshness lifetime for url
+func (t *Transport) ttl(url string) time.Duration {
+	for _, rule := range t.TTLs {
+		if strings.Contains(url, rule.Match) {
+			return rule.TTL
+		}
+	}
+	return DefaultTTL
+}
+
+// path returns the file holding the entry for url. Entries of a repository are kept together
+// under its repoDir, so a write to the repository removes one directory.
+func (t *Transport) path(url string) string {
+	key := hash(url)
+	if root := repoRoot(url); root != "" {
+		return filepath.Join(t.repoDir(root), key+".json")
+	}
+	return filepath.Join(t.Dir, key[:2], key+".json")
+}
+
+// repoDir returns the directory holding the entries of the repository at root
+func (t *Transport) repoDir(root string) string {
+	return filepath.Join(t.Dir, "repos", hash(root))
+}
+
+// hash returns the hex SHA-256 of s, used for file and directory names
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// load returns the cached response for url, or nil when there is none
+func (t *Transport) load(url string, req *http.Request) (*http.Response, time.Time) {
+	data, err := os.ReadFile(t.path(url))
+	if err != nil {
+		return nil, time.Time{}
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.URL != url {
+		return nil, time.Time{}
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(e.Response)), req)
+	if err != nil {
+		return nil, time.Time{}
+	}
+	return resp, e.StoredAt
+}
+
+// store saves a successful response and returns an equivalent response with a fresh body
+func (t *Transport) store(url string, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	wire, err := httputil.DumpResponse(resp, true)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, nil
+	}
+	_ = t.write(entry{URL: url, StoredAt: t.now(), Response: wire})
+	return resp, nil
+}
+
+// touch marks a revalidated entry as fresh again
+func (t *Transport) touch(url string) {
+	data, err := os.ReadFile(t.path(url))
+	if err != nil {
+		return
+	}
+	var e entry
+	if json.Unmarshal(data, &e) == nil {
+		e.StoredAt = t.now()
+		_ = t.write(e)
+	}
+}
+
+// write stores e atomically so concurrent readers never see a partial file
+func (t *Transport) write(e entry) error {
+	path := t.path(e.URL)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
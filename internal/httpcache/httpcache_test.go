@@ -0,0 +1,162 @@
+package httpcache
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransportRevalidates(t *testing.T) {
+	requests, conditional := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = io.WriteString(w, `{"Version":"v1.2.3"}`)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := New(t.TempDir(), http.DefaultTransport)
+	tr.TTLs = []TTLRule{{Match: "/", TTL: time.Minute}}
+	tr.now = func() time.Time { return now }
+	client := &http.Client{Transport: tr}
+
+	get := func() (string, string) {
+		resp, err := client.Get(srv.URL + "/mod/@latest")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body), resp.Header.Get(Header)
+	}
+
+	if body, state := get(); body != `{"Version":"v1.2.3"}` || state != "" {
+		t.Fatalf("first request: %q %q", body, state)
+	}
+	if body, state := get(); body != `{"Version":"v1.2.3"}` || state != Hit || requests != 1 {
+		t.Fatalf("fresh entry not served from cache: %q %q after %d requests", body, state, requests)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if body, state := get(); body != `{"Version":"v1.2.3"}` || state != Revalidated || conditional != 1 {
+		t.Fatalf("stale entry not revalidated: %q %q, %d conditional requests", body, state, conditional)
+	}
+	if _, state := get(); state != Hit || requests != 2 {
+		t.Fatalf("revalidated entry should be fresh again: %q after %d requests", state, requests)
+	}
+
+	// Cache-Control: no-cache revalidates even a fresh entry
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/mod/@latest", nil)
+	req.Header.Set("Cache-Control", "no-cache")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if state := resp.Header.Get(Header); state != Revalidated || conditional != 2 {
+		t.Fatalf("no-cache request served without revalidation: %q, %d conditional requests", state, conditional)
+	}
+}
+
+func TestTransportOffline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "cached")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	online := New(dir, http.DefaultTransport)
+	if resp, err := (&http.Client{Transport: online}).Get(srv.URL + "/a"); err != nil {
+		t.Fatal(err)
+	} else {
+		resp.Body.Close()
+	}
+	srv.Close()
+
+	offline := New(dir, http.DefaultTransport)
+	offline.Offline = true
+	offline.now = func() time.Time { return time.Now().Add(24 * time.Hour) }
+	client := &http.Client{Transport: offline}
+	resp, err := client.Get(srv.URL + "/a")
+	if err != nil {
+		t.Fatalf("expected the expired entry offline, got %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "cached" || resp.Header.Get(Header) != Stale {
+		t.Fatalf("unexpected offline response: %q %q", body, resp.Header.Get(Header))
+	}
+	if _, err := client.Get(srv.URL + "/b"); !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline for an uncached URL, got %v", err)
+	}
+}
+
+func TestTransportWriteInvalidatesRepository(t *testing.T) {
+	releases := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			releases++
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		_, _ = io.WriteString(w, strconv.Itoa(releases))
+	}))
+	defer srv.Close()
+
+	tr := New(t.TempDir(), http.DefaultTransport)
+	tr.TTLs = []TTLRule{{Match: "/", TTL: time.Hour}}
+	client := &http.Client{Transport: tr}
+	get := func(path string) (string, string) {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body), resp.Header.Get(Header)
+	}
+
+	get("/repos/owner/repo/releases?per_page=100")
+	get("/repos/owner/other/releases")
+	if body, state := get("/repos/owner/repo/releases?per_page=100"); body != "0" || state != Hit {
+		t.Fatalf("expected a cached listing, got %q %q", body, state)
+	}
+	resp, err := client.Post(srv.URL+"/repos/owner/repo/releases", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if body, state := get("/repos/owner/repo/releases?per_page=100"); body != "1" || state != "" {
+		t.Fatalf("expected the listing to be refetched after the write, got %q %q", body, state)
+	}
+	if _, state := get("/repos/owner/other/releases"); state != Hit {
+		t.Fatalf("expected other repositories to stay cached, got %q", state)
+	}
+}
+
+func TestDefaultTTLs(t *testing.T) {
+	tr := New(t.TempDir(), http.DefaultTransport)
+	cases := map[string]time.Duration{
+		"https://proxy.golang.org/github.com/a/b/@v/v1.0.0.info": 30 * 24 * time.Hour,
+		"https://proxy.golang.org/github.com/a/b/@v/list":        0,
+		"https://proxy.golang.org/github.com/a/b/@latest":        0,
+		"https://api.github.com/repos/a/b/releases?per_page=100": 0,
+		"https://api.github.com/repos/a/b/pulls/1":               0,
+	}
+	for url, want := range cases {
+		if got := tr.ttl(url); got != want {
+			t.Errorf("ttl(%s) = %s, want %s", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/httpcache"
+)
+
+// UserAgent identifies jfrm to the module proxy and the GitHub API
+const UserAgent = "jfrm (+https://github.com/bhanurp/jfrm)"
+
+// Defaults of New
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultMaxRetries = 3
+	DefaultBaseDelay  = time.Second
+	DefaultMaxDelay   = time.Minute
+)
+
+// Client sends HTTP requests with a user agent, retries transient failures with
+// exponential backoff and waits out rate limits
+type Client struct {
+	// HTTP performs the requests; its transport defaults to http.DefaultTransport,
+	// which is where the on-disk cache is installed
+	HTTP *http.Client
+	// MaxRetries is how many times a failed request is retried
+	MaxRetries int
+	// BaseDelay is the first backoff delay; it doubles with every retry
+	BaseDelay time.Duration
+	// MaxDelay caps a single wait; rate limits resetting later are not waited for
+	MaxDelay time.Duration
+	// Debug logs every request, its status and duration
+	Debug bool
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New creates a client with the default timeout, retries and delays
+func New() *Client {
+	return &Client{
+		HTTP:       &http.Client{Timeout: DefaultTimeout},
+		MaxRetries: DefaultMaxRetries,
+		BaseDelay:  DefaultBaseDelay,
+		MaxDelay:   DefaultMaxDelay,
+		now:        time.Now,
+		sleep:      sleepContext,
+	}
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultClient = New()
+)
+
+// Default returns the client shared by all API packages
+func Default() *Client {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultClient
+}
+
+// SetDefault replaces the client returned by Default
+func SetDefault(c *Client) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultClient = c
+}
+
+// Get sends a GET request for url bound to ctx
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do sends req, retrying network errors and 5xx responses of idempotent requests with
+// exponential backoff and waiting for Retry-After or X-RateLimit-Reset on 429 and
+// rate-limited 403 responses. POST and PATCH requests are only retried on rate limits, where
+// the server did not act on them, so a timeout never creates a PR or release twice.
+// Request bodies must be replayable (see http.Request.GetBody) to be retried.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", UserAgent)
+	}
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		start := c.now()
+		resp, err := c.HTTP.Do(req)
+		if c.Debug {
+			if err != nil {
+				log.Printf("[http] %s %s failed after %s: %v", req.Method, req.URL, c.now().Sub(start).Round(time.Millisecond), err)
+			} else {
+				log.Printf("[http] %s %s -> %s (%s)", req.Method, req.URL, resp.Status, c.now().Sub(start).Round(time.Millisecond))
+			}
+		}
+
+		wait, retry := c.retryDelay(resp, err, attempt, idempotent(req.Method))
+		if !retry || attempt >= c.MaxRetries || (attempt > 0 && req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		if c.Debug {
+			log.Printf("[http] retrying %s %s in %s", req.Method, req.URL, wait.Round(time.Millisecond))
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// idempotent reports whether sending a request with method twice has the same effect as once
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryDelay decides whether a response or error is worth retrying and how long to wait first.
+// Network errors and server errors may come after the server acted, so they are only retried
+// for idempotent requests.
+func (c *Client) retryDelay(resp *http.Response, err error, attempt int, idempotent bool) (time.Duration, bool) {
+	if err != nil {
+		if !idempotent || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, httpcache.ErrOffline) {
+			return 0, false
+		}
+		return c.backoff(attempt), true
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && isRateLimited(resp):
+		wait, ok := c.rateLimitDelay(resp)
+		if !ok {
+			wait = c.backoff(attempt)
+		}
+		return wait, wait <= c.MaxDelay
+	case resp.StatusCode >= 500 && idempotent:
+		return c.backoff(attempt), true
+	}
+	return 0, false
+}
+
+// backoff returns the exponential delay for attempt with up to 50% jitter
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.BaseDelay << attempt
+	if d > c.MaxDelay || d <= 0 {
+		d = c.MaxDelay
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// rateLimitDelay reads how long the server asks us to wait
+func (c *Client) rateLimitDelay(resp *http.Response) (time.Duration, bool) {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return at.Sub(c.now()), true
+		}
+	}
+	if v := resp.Header.Get("X-RateLimit-Reset"); v != "" {
+		if epoch, err := strconv.ParseInt(v, 10, 64); err == nil {
+			d := time.Unix(epoch, 0).Sub(c.now())
+			if d < 0 {
+				d = 0
+			}
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// isRateLimited reports whether a 403 is GitHub's rate limit rather than a permission error
+func isRateLimited(resp *http.Response) bool {
+	return resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != ""
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,186 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client that records its waits instead of sleeping
+func newTestClient(waits *[]time.Duration) *Client {
+	c := New()
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	return c
+}
+
+func TestDoRetriesServerErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != UserAgent {
+			t.Errorf("User-Agent = %q", r.Header.Get("User-Agent"))
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	resp, err := newTestClient(&waits).Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 || len(waits) != 2 {
+		t.Errorf("status %d after %d calls and %d waits, want 200 after 3 calls and 2 waits", resp.StatusCode, calls, len(waits))
+	}
+	if waits[1] < 2*DefaultBaseDelay {
+		t.Errorf("second wait %s did not back off", waits[1])
+	}
+}
+
+func TestDoDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	resp, err := newTestClient(&waits).Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || calls != 1 {
+		t.Errorf("status %d after %d calls, want 404 after 1 call", resp.StatusCode, calls)
+	}
+}
+
+func TestDoWaitsForRateLimit(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(20*time.Second).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	c := newTestClient(&waits)
+	c.now = func() time.Time { return now }
+	resp, err := c.Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(waits) != 2 || waits[0] != 7*time.Second || waits[1] != 20*time.Second {
+		t.Errorf("status %d with waits %v, want 200 after waiting 7s and 20s", resp.StatusCode, waits)
+	}
+}
+
+func TestDoGivesUpOnDistantRateLimitReset(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	resp, err := newTestClient(&waits).Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || len(waits) != 0 {
+		t.Errorf("status %d with waits %v, want the 429 without waiting", resp.StatusCode, waits)
+	}
+}
+
+func TestDoDoesNotResendPostOnServerError(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 && r.Header.Get("X-Rate") != "" {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	c := newTestClient(&waits)
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"title":"release"}`))
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || calls != 1 || len(waits) != 0 {
+		t.Errorf("POST sent %d times with %d waits, want once without retrying the 502", calls, len(waits))
+	}
+
+	// A rate-limited POST was not processed and is sent again
+	calls = 0
+	req, _ = http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"title":"release"}`))
+	req.Header.Set("X-Rate", "1")
+	resp, err = c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if calls != 2 || len(waits) != 1 {
+		t.Errorf("rate-limited POST sent %d times with %d waits, want 2 and 1", calls, len(waits))
+	}
+}
+
+func TestDoJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "token secret" || r.Header.Get("Accept") != "application/vnd.test+json" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"v1.0.0"}`))
+	}))
+	defer srv.Close()
+
+	authorize := func(req *http.Request) error {
+		req.Header.Set("Accept", "application/vnd.test+json")
+		req.Header.Set("Authorization", "token secret")
+		return nil
+	}
+	var out struct {
+		Name string `json:"name"`
+	}
+	if err := DoJSON(http.MethodGet, srv.URL+"/release", authorize, nil, &out); err != nil || out.Name != "v1.0.0" {
+		t.Fatalf("unexpected result %+v (err %v)", out, err)
+	}
+	if err := DoJSON(http.MethodGet, srv.URL+"/missing", authorize, nil, &out); !IsNotFound(err) {
+		t.Fatalf("expected a not-found error, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+)
+
+// APIError reports an unexpected HTTP status from a JSON API
+type APIError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("unexpected response: %s", e.Status)
+}
+
+// IsNotFound reports whether err is a 404 from a JSON API
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// DoJSON sends an API request through the default client, letting authorize add credentials
+// and API-specific headers, and decodes the JSON response into out when out is not nil
+func DoJSON(method, url string, authorize func(*http.Request) error, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewBuffer(data)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if err := authorize(req); err != nil {
+		return err
+	}
+
+	resp, err := Default().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}(resp.Body)
+	if resp.StatusCode >= 300 {
+		return &APIError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// NoCache wraps authorize so that requests also bypass fresh entries of the on-disk cache,
+// for state that is polled until it changes
+func NoCache(authorize func(*http.Request) error) func(*http.Request) error {
+	return func(req *http.Request) error {
+		req.Header.Set("Cache-Control", "no-cache")
+		return authorize(req)
+	}
+}
@@ -0,0 +1,95 @@
+package impact
+
+import (
+	"sort"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/registry"
+)
+
+// Status describes how a repository is affected by a release
+type Status string
+
+const (
+	// StatusOutdated means the repository requires an older version of the released module
+	StatusOutdated Status = "outdated"
+	// StatusTransitive means the repository requires another repository that needs a bump
+	StatusTransitive Status = "transitive"
+	// StatusUpToDate means the repository already requires the released version or newer
+	StatusUpToDate Status = "up-to-date"
+	// StatusUnaffected means the repository does not depend on the released module
+	StatusUnaffected Status = "unaffected"
+	// StatusUnknown means the repository's go.mod could not be read
+	StatusUnknown Status = "unknown"
+)
+
+// Result is the impact of a release on one repository
+type Result struct {
+	Module string `json:"module"`
+	Slug   string `json:"slug"`
+	Status Status `json:"status"`
+	// Current is the version of the released module the repository requires, if any
+	Current string `json:"current,omitempty"`
+	// Via lists the outdated registered modules the repository requires when the impact is transitive
+	Via []string `json:"via,omitempty"`
+	// Error is set when the repository's go.mod could not be read
+	Error string `json:"error,omitempty"`
+}
+
+// NeedsBump reports whether the repository has to be updated after the release
+func (r Result) NeedsBump() bool {
+	return r.Status == StatusOutdated || r.Status == StatusTransitive
+}
+
+// Analyze determines which repositories are behind module@version. requirements maps each
+// repository's module to the requirements of its go.mod; repositories missing from it are
+// reported as unknown with errs[module].
+func Analyze(module, version string, repos []registry.Repository, requirements map[string]map[string]string, errs map[string]error) []Result {
+	results := make([]Result, 0, len(repos))
+	index := make(map[string]int)
+	impacted := make(map[string]bool)
+	for _, repo := range repos {
+		if repo.Module == module {
+			continue
+		}
+		res := Result{Module: repo.Module, Slug: repo.Slug, Status: StatusUnaffected}
+		if err := errs[repo.Module]; err != nil {
+			res.Status = StatusUnknown
+			res.Error = err.Error()
+		} else if current, ok := requirements[repo.Module][module]; ok {
+			res.Current = current
+			res.Status = StatusUpToDate
+			if deps.IsNewerVersion(current, version) {
+				res.Status = StatusOutdated
+				impacted[repo.Module] = true
+			}
+		}
+		index[repo.Module] = len(results)
+		results = append(results, res)
+	}
+
+	// Propagate through repositories that require an impacted repository
+	for changed := true; changed; {
+		changed = false
+		for i := range results {
+			res := &results[i]
+			if impacted[res.Module] || res.Status == StatusUnknown {
+				continue
+			}
+			for req := range requirements[res.Module] {
+				if impacted[req] {
+					res.Via = append(res.Via, req)
+				}
+			}
+			if len(res.Via) > 0 {
+				sort.Strings(res.Via)
+				if res.Status != StatusOutdated {
+					res.Status = StatusTransitive
+				}
+				impacted[res.Module] = true
+				changed = true
+			}
+		}
+	}
+	return results
+}
@@ -0,0 +1,50 @@
+package impact
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bhanurp/jfrm/internal/registry"
+)
+
+func TestAnalyze(t *testing.T) {
+	repos := []registry.Repository{
+		{Module: "m/client", Slug: "o/client"},
+		{Module: "m/core", Slug: "o/core"},
+		{Module: "m/plugin", Slug: "o/plugin"},
+		{Module: "m/cli", Slug: "o/cli"},
+		{Module: "m/other", Slug: "o/other"},
+		{Module: "m/broken", Slug: "o/broken"},
+	}
+	requirements := map[string]map[string]string{
+		"m/client": {},
+		"m/core":   {"m/client": "v1.0.0"},
+		"m/plugin": {"m/client": "v1.1.0", "m/core": "v2.0.0"},
+		"m/cli":    {"m/plugin": "v0.3.0"},
+		"m/other":  {"example.com/x": "v1.0.0"},
+	}
+	errs := map[string]error{"m/broken": errors.New("not found")}
+
+	results := Analyze("m/client", "v1.1.0", repos, requirements, errs)
+	want := map[string]Status{
+		"m/core":   StatusOutdated,
+		"m/plugin": StatusTransitive,
+		"m/cli":    StatusTransitive,
+		"m/other":  StatusUnaffected,
+		"m/broken": StatusUnknown,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(results))
+	}
+	for _, r := range results {
+		if r.Status != want[r.Module] {
+			t.Fatalf("%s: expected %s, got %s", r.Module, want[r.Module], r.Status)
+		}
+	}
+	if results[1].Via[0] != "m/core" || results[1].Current != "v1.1.0" {
+		t.Fatalf("unexpected plugin result: %+v", results[1])
+	}
+	if results[4].Error == "" {
+		t.Fatalf("expected error to be reported for m/broken")
+	}
+}
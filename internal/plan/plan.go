@@ -0,0 +1,190 @@
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+)
+
+// DefaultFile is where plans are written by default
+const DefaultFile = ".jfrm/plan.json"
+
+// PullRequest is the metadata of the pull request a plan opens
+type PullRequest struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Plan describes a dependency update to apply
+type Plan struct {
+	Repository    string `json:"repository"`
+	BaseRemote    string `json:"baseRemote"`
+	BaseBranch    string `json:"baseBranch"`
+	Branch        string `json:"branch"`
+	LatestRelease string `json:"latestRelease"`
+	NextVersion   string `json:"nextVersion"`
+	// TagPrefix is the release tag prefix the next version is tagged with; empty means "v"
+	TagPrefix string        `json:"tagPrefix,omitempty"`
+	Updates   []deps.Update `json:"updates"`
+	Changelog string        `json:"changelog,omitempty"`
+	// ChangelogEntries holds the Keep-a-Changelog entries to add when Changelog is set
+	ChangelogEntries map[string][]string `json:"changelogEntries,omitempty"`
+	CreatePR         bool                `json:"createPR"`
+	PullRequest      PullRequest         `json:"pullRequest"`
+	CreatedAt        time.Time           `json:"createdAt"`
+}
+
+// Load reads a plan file
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan %s: %w", path, err)
+	}
+	p := &Plan{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Save writes the plan file
+func (p *Plan) Save(path string) error {
+	return writeJSON(path, p)
+}
+
+// Checksum identifies the plan content so that a state file is not reused for another plan
+func (p *Plan) Checksum() string {
+	data, _ := json.Marshal(p)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Status is the outcome of a step
+type Status string
+
+const (
+	StatusDone   Status = "done"
+	StatusFailed Status = "failed"
+)
+
+// StepState records the outcome of one step
+type StepState struct {
+	Name   string    `json:"name"`
+	Status Status    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// State records the progress of applying a plan
+type State struct {
+	PlanChecksum string       `json:"planChecksum"`
+	Steps        []*StepState `json:"steps"`
+	// PR is the number of the pull request once it has been created
+	PR   int `json:"pr,omitempty"`
+	path string
+}
+
+// StatePath returns the state file used for a plan file
+func StatePath(planPath string) string {
+	return planPath + ".state"
+}
+
+// LoadState reads the progress for p from path, starting fresh when the file does not exist.
+// A state recorded for a different plan is rejected.
+func LoadState(path string, p *Plan) (*State, error) {
+	s := &State{PlanChecksum: p.Checksum(), path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state %s: %w", path, err)
+	}
+	saved := &State{path: path}
+	if err := json.Unmarshal(data, saved); err != nil {
+		return nil, fmt.Errorf("failed to parse state %s: %w", path, err)
+	}
+	if saved.PlanChecksum != s.PlanChecksum {
+		return nil, fmt.Errorf("state %s belongs to a different plan; remove it to start over", path)
+	}
+	return saved, nil
+}
+
+// Save writes the state file
+func (s *State) Save() error {
+	return writeJSON(s.path, s)
+}
+
+// Done reports whether a step has completed
+func (s *State) Done(name string) bool {
+	st := s.step(name)
+	return st != nil && st.Status == StatusDone
+}
+
+func (s *State) step(name string) *StepState {
+	for _, st := range s.Steps {
+		if st.Name == name {
+			return st
+		}
+	}
+	return nil
+}
+
+func (s *State) record(name string, err error) {
+	st := s.step(name)
+	if st == nil {
+		st = &StepState{Name: name}
+		s.Steps = append(s.Steps, st)
+	}
+	st.At = time.Now()
+	st.Status, st.Error = StatusDone, ""
+	if err != nil {
+		st.Status, st.Error = StatusFailed, err.Error()
+	}
+}
+
+// Step is a named, resumable unit of work
+type Step struct {
+	Name string
+	Run  func() error
+}
+
+// Apply runs the steps in order, skipping the ones already done and saving the state after
+// each step. It stops at the first failing step so that the next run resumes there.
+func (s *State) Apply(steps []Step, progress func(name string, skipped bool)) error {
+	for _, step := range steps {
+		if s.Done(step.Name) {
+			progress(step.Name, true)
+			continue
+		}
+		progress(step.Name, false)
+		err := step.Run()
+		s.record(step.Name, err)
+		if saveErr := s.Save(); saveErr != nil {
+			return fmt.Errorf("failed to save state: %w", saveErr)
+		}
+		if err != nil {
+			return fmt.Errorf("step %s failed: %w", step.Name, err)
+		}
+	}
+	return nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
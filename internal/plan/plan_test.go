@@ -0,0 +1,62 @@
+package plan
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+)
+
+func TestApplyResumes(t *testing.T) {
+	dir := t.TempDir()
+	p := &Plan{Repository: "owner/repo", Branch: "update-dependencies-1.2.4", Updates: []deps.Update{{Module: "m", From: "v1", To: "v2"}}}
+	planPath := filepath.Join(dir, "plan.json")
+	if err := p.Save(planPath); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(planPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runs := map[string]int{}
+	failPush := true
+	steps := []Step{
+		{Name: "commit", Run: func() error { runs["commit"]++; return nil }},
+		{Name: "push", Run: func() error {
+			runs["push"]++
+			if failPush {
+				return errors.New("network down")
+			}
+			return nil
+		}},
+		{Name: "create-pr", Run: func() error { runs["create-pr"]++; return nil }},
+	}
+	noop := func(string, bool) {}
+
+	state, err := LoadState(StatePath(planPath), loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Apply(steps, noop); err == nil {
+		t.Fatalf("expected push failure")
+	}
+
+	failPush = false
+	state, err = LoadState(StatePath(planPath), loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Apply(steps, noop); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs["commit"] != 1 || runs["push"] != 2 || runs["create-pr"] != 1 {
+		t.Fatalf("unexpected step runs: %v", runs)
+	}
+
+	p.NextVersion = "9.9.9"
+	if _, err := LoadState(StatePath(planPath), p); err == nil {
+		t.Fatalf("expected state of another plan to be rejected")
+	}
+}
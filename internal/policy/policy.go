@@ -0,0 +1,273 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/blang/semver/v4"
+	"golang.org/x/mod/module"
+	modsemver "golang.org/x/mod/semver"
+)
+
+// Rule names a verification rule
+type Rule string
+
+// Verification rules evaluated by Evaluate
+const (
+	RuleMinorLag      Rule = "minor-lag"
+	RuleDaysBehind    Rule = "days-behind"
+	RulePseudoVersion Rule = "pseudo-version"
+	RuleRetracted     Rule = "retracted"
+	RuleVulnerability Rule = "vulnerability"
+	// RuleUnresolved reports a dependency whose facts could not be looked up
+	RuleUnresolved Rule = "unresolved"
+)
+
+// Rules lists every rule in reporting order
+var Rules = []Rule{RuleMinorLag, RuleDaysBehind, RulePseudoVersion, RuleRetracted, RuleVulnerability, RuleUnresolved}
+
+// Severity orders how serious a violation is
+type Severity int
+
+// Severities from least to most serious
+const (
+	SeverityNone Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// Exit codes of the verify command; 1 stays reserved for jfrm failures
+const (
+	ExitWarning = 2
+	ExitError   = 3
+)
+
+// String returns the configuration name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "none"
+}
+
+// ExitCode returns the process exit code for a run whose worst violation has this severity
+func (s Severity) ExitCode() int {
+	switch s {
+	case SeverityWarning:
+		return ExitWarning
+	case SeverityError:
+		return ExitError
+	}
+	return 0
+}
+
+// ParseSeverity converts "off", "warning" or "error" into a Severity
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "off", "none":
+		return SeverityNone, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return SeverityNone, fmt.Errorf("unknown severity %q; expected off, warning or error", name)
+}
+
+// Policy holds the verification rules applied to the managed dependencies
+type Policy struct {
+	// MaxMinorLag is the number of minor versions a dependency may trail its latest release (negative disables)
+	MaxMinorLag int
+	// MaxDaysBehind is how many days the first newer version may have been available (negative disables)
+	MaxDaysBehind int
+	// DisallowPseudoVersions rejects pseudo-versions on release branches
+	DisallowPseudoVersions bool
+	// ReleaseBranches are path.Match patterns of the branches treated as release branches
+	ReleaseBranches []string
+	// CheckRetracted rejects versions retracted by the module's latest go.mod
+	CheckRetracted bool
+	// CheckVulnerabilities rejects versions with known vulnerabilities
+	CheckVulnerabilities bool
+	// Severities assigns a severity to each rule
+	Severities map[Rule]Severity
+}
+
+// DefaultPolicy returns the policy used when nothing is configured: lag rules are off,
+// pseudo-versions, retractions and vulnerabilities are errors
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxMinorLag:            -1,
+		MaxDaysBehind:          -1,
+		DisallowPseudoVersions: true,
+		ReleaseBranches:        []string{"release/*"},
+		CheckRetracted:         true,
+		CheckVulnerabilities:   true,
+		Severities: map[Rule]Severity{
+			RuleMinorLag:      SeverityError,
+			RuleDaysBehind:    SeverityWarning,
+			RulePseudoVersion: SeverityError,
+			RuleRetracted:     SeverityError,
+			RuleVulnerability: SeverityError,
+			RuleUnresolved:    SeverityWarning,
+		},
+	}
+}
+
+// IsReleaseBranch reports whether branch matches one of the release branch patterns
+func (p Policy) IsReleaseBranch(branch string) bool {
+	for _, pattern := range p.ReleaseBranches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Retraction is a version interval retracted by a module author
+type Retraction struct {
+	Low       string
+	High      string
+	Rationale string
+}
+
+// Vulnerability is a known advisory affecting a module version
+type Vulnerability struct {
+	ID      string `json:"id"`
+	Summary string `json:"summary"`
+}
+
+// Source looks up the facts the rules are evaluated against
+type Source interface {
+	Latest(module string) (string, time.Time, error)
+	// FirstNewer returns the oldest version newer than current and when it was published
+	FirstNewer(module, current string) (string, time.Time, error)
+	Retractions(module string) ([]Retraction, error)
+	Vulnerabilities(module, version string) ([]Vulnerability, error)
+}
+
+// Violation is a dependency that breaks a rule
+type Violation struct {
+	Module   string   `json:"module"`
+	Version  string   `json:"version"`
+	Rule     Rule     `json:"rule"`
+	Severity Severity `json:"-"`
+	Message  string   `json:"message"`
+}
+
+// Evaluate checks the managed dependencies against the policy. releaseBranch enables the
+// rules that only apply to release branches. Violations are sorted by module and rule.
+func (p Policy) Evaluate(dependencies map[string]string, releaseBranch bool, src Source, now time.Time) []Violation {
+	var violations []Violation
+	add := func(mod, ver string, rule Rule, format string, args ...interface{}) {
+		severity := p.Severities[rule]
+		if severity == SeverityNone {
+			return
+		}
+		violations = append(violations, Violation{Module: mod, Version: ver, Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for mod, current := range dependencies {
+		if !deps.IsAllowedDependency(mod) {
+			continue
+		}
+		if releaseBranch && p.DisallowPseudoVersions && module.IsPseudoVersion(current) {
+			add(mod, current, RulePseudoVersion, "pseudo-version is not allowed on a release branch")
+		}
+
+		if p.MaxMinorLag >= 0 || p.MaxDaysBehind >= 0 {
+			latest, _, err := src.Latest(mod)
+			if err != nil {
+				add(mod, current, RuleUnresolved, "failed to resolve latest version: %v", err)
+			} else if deps.IsNewerVersion(current, latest) {
+				if lag, major := minorLag(current, latest); p.MaxMinorLag >= 0 && (major || lag > p.MaxMinorLag) {
+					if major {
+						add(mod, current, RuleMinorLag, "a new major version %s is available", latest)
+					} else {
+						add(mod, current, RuleMinorLag, "%d minor version(s) behind %s (max %d)", lag, latest, p.MaxMinorLag)
+					}
+				}
+				if p.MaxDaysBehind >= 0 {
+					// The dependency has been behind since the first version newer than it was published
+					if newer, published, err := src.FirstNewer(mod, current); err != nil {
+						add(mod, current, RuleUnresolved, "failed to resolve the first newer version: %v", err)
+					} else if days := int(now.Sub(published).Hours() / 24); !published.IsZero() && days > p.MaxDaysBehind {
+						add(mod, current, RuleDaysBehind, "behind for %d day(s) since %s was published (latest %s, max %d)", days, newer, latest, p.MaxDaysBehind)
+					}
+				}
+			}
+		}
+
+		if p.CheckRetracted {
+			retractions, err := src.Retractions(mod)
+			if err != nil {
+				add(mod, current, RuleUnresolved, "failed to read retractions: %v", err)
+			}
+			for _, r := range retractions {
+				if modsemver.Compare(current, r.Low) >= 0 && modsemver.Compare(current, r.High) <= 0 {
+					msg := "version is retracted"
+					if r.Rationale != "" {
+						msg += ": " + r.Rationale
+					}
+					add(mod, current, RuleRetracted, "%s", msg)
+					break
+				}
+			}
+		}
+
+		if p.CheckVulnerabilities {
+			vulns, err := src.Vulnerabilities(mod, current)
+			if err != nil {
+				add(mod, current, RuleUnresolved, "failed to query vulnerabilities: %v", err)
+			}
+			for _, v := range vulns {
+				add(mod, current, RuleVulnerability, "%s: %s", v.ID, v.Summary)
+			}
+		}
+	}
+
+	order := make(map[Rule]int, len(Rules))
+	for i, r := range Rules {
+		order[r] = i
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Module != violations[j].Module {
+			return violations[i].Module < violations[j].Module
+		}
+		return order[violations[i].Rule] < order[violations[j].Rule]
+	})
+	return violations
+}
+
+// Worst returns the highest severity among the violations
+func Worst(violations []Violation) Severity {
+	worst := SeverityNone
+	for _, v := range violations {
+		if v.Severity > worst {
+			worst = v.Severity
+		}
+	}
+	return worst
+}
+
+// minorLag returns how many minor versions current trails latest, and whether latest is a newer major
+func minorLag(current, latest string) (int, bool) {
+	c, err1 := semver.ParseTolerant(current)
+	l, err2 := semver.ParseTolerant(latest)
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+	if l.Major != c.Major {
+		return 0, l.Major > c.Major
+	}
+	if l.Minor < c.Minor {
+		return 0, false
+	}
+	return int(l.Minor - c.Minor), false
+}
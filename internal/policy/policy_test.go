@@ -0,0 +1,116 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeSource struct {
+	latest map[string]string
+	// newer is the first version newer than the current one of each module
+	newer map[string]string
+	// published maps module@version to its publish time
+	published   map[string]time.Time
+	retractions map[string][]Retraction
+	vulns       map[string][]Vulnerability
+}
+
+func (f fakeSource) Latest(module string) (string, time.Time, error) {
+	v, ok := f.latest[module]
+	if !ok {
+		return "", time.Time{}, errors.New("not found")
+	}
+	return v, f.published[module+"@"+v], nil
+}
+
+func (f fakeSource) FirstNewer(module, current string) (string, time.Time, error) {
+	v, ok := f.newer[module]
+	if !ok {
+		return "", time.Time{}, errors.New("not found")
+	}
+	return v, f.published[module+"@"+v], nil
+}
+
+func (f fakeSource) Retractions(module string) ([]Retraction, error) {
+	return f.retractions[module], nil
+}
+
+func (f fakeSource) Vulnerabilities(module, version string) ([]Vulnerability, error) {
+	return f.vulns[module+"@"+version], nil
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	src := fakeSource{
+		latest: map[string]string{
+			"github.com/jfrog/jfrog-client-go": "v1.50.0",
+			"github.com/jfrog/gofrog":          "v1.7.6",
+		},
+		newer: map[string]string{
+			"github.com/jfrog/jfrog-client-go": "v1.48.0",
+			"github.com/jfrog/gofrog":          "v1.7.6",
+		},
+		// jfrog-client-go's latest release is recent, but it has been behind since v1.48.0
+		published: map[string]time.Time{
+			"github.com/jfrog/jfrog-client-go@v1.50.0": now.AddDate(0, 0, -5),
+			"github.com/jfrog/jfrog-client-go@v1.48.0": now.AddDate(0, 0, -40),
+			"github.com/jfrog/gofrog@v1.7.6":           now.AddDate(0, 0, -40),
+		},
+		retractions: map[string][]Retraction{
+			"github.com/jfrog/gofrog": {{Low: "v1.7.4", High: "v1.7.5", Rationale: "broken build"}},
+		},
+		vulns: map[string][]Vulnerability{
+			"github.com/jfrog/jfrog-client-go@v1.47.0": {{ID: "GO-2025-0001", Summary: "path traversal"}},
+		},
+	}
+	dependencies := map[string]string{
+		"github.com/jfrog/jfrog-client-go": "v1.47.0",
+		"github.com/jfrog/gofrog":          "v1.7.5",
+		"github.com/jfrog/build-info-go":   "v1.9.1-0.20250101000000-abcdefabcdef",
+		"example.com/other":                "v0.0.0-20250101000000-abcdefabcdef",
+	}
+
+	p := DefaultPolicy()
+	p.MaxMinorLag = 2
+	p.MaxDaysBehind = 30
+	got := p.Evaluate(dependencies, true, src, now)
+
+	want := []struct {
+		module string
+		rule   Rule
+	}{
+		{"github.com/jfrog/build-info-go", RulePseudoVersion},
+		{"github.com/jfrog/build-info-go", RuleUnresolved},
+		{"github.com/jfrog/gofrog", RuleDaysBehind},
+		{"github.com/jfrog/gofrog", RuleRetracted},
+		{"github.com/jfrog/jfrog-client-go", RuleMinorLag},
+		{"github.com/jfrog/jfrog-client-go", RuleDaysBehind},
+		{"github.com/jfrog/jfrog-client-go", RuleVulnerability},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d violations, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Module != w.module || got[i].Rule != w.rule {
+			t.Errorf("violation %d = %s %s, want %s %s", i, got[i].Module, got[i].Rule, w.module, w.rule)
+		}
+	}
+	if Worst(got).ExitCode() != ExitError {
+		t.Fatalf("expected error exit code, got %d", Worst(got).ExitCode())
+	}
+
+	// Off a release branch pseudo-versions are allowed
+	for _, v := range p.Evaluate(dependencies, false, src, now) {
+		if v.Rule == RulePseudoVersion {
+			t.Fatalf("unexpected pseudo-version violation off a release branch: %+v", v)
+		}
+	}
+}
+
+func TestIsReleaseBranch(t *testing.T) {
+	p := DefaultPolicy()
+	if !p.IsReleaseBranch("release/2.60") || p.IsReleaseBranch("dev") {
+		t.Fatalf("unexpected release branch matching for %v", p.ReleaseBranches)
+	}
+}
@@ -0,0 +1,112 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/httpclient"
+	"github.com/bhanurp/jfrm/internal/resolver"
+	"golang.org/x/mod/modfile"
+	modsemver "golang.org/x/mod/semver"
+)
+
+// osvQueryURL is the OSV endpoint queried for known vulnerabilities
+var osvQueryURL = "https://api.osv.dev/v1/query"
+
+// ProxySource reads versions and retractions from the Go module proxy and
+// vulnerabilities from OSV (osv.dev)
+type ProxySource struct{}
+
+// Latest returns the latest version of a module and when it was published, through the shared resolver
+func (ProxySource) Latest(module string) (string, time.Time, error) {
+	return resolver.Shared().Latest(context.Background(), module)
+}
+
+// FirstNewer returns the lowest release version of a module newer than current and when it was
+// published, from the proxy's version list
+func (ProxySource) FirstNewer(module, current string) (string, time.Time, error) {
+	versions, err := deps.ListModuleVersions(module)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	first := ""
+	for _, v := range versions {
+		if !modsemver.IsValid(v) || modsemver.Prerelease(v) != "" || modsemver.Compare(v, current) <= 0 {
+			continue
+		}
+		if first == "" || modsemver.Compare(v, first) < 0 {
+			first = v
+		}
+	}
+	if first == "" {
+		return "", time.Time{}, fmt.Errorf("no release of %s newer than %s is listed by the module proxy", module, current)
+	}
+	published, err := deps.GetModuleVersionTime(module, first)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return first, published, nil
+}
+
+// Retractions returns the retract directives of the module's latest go.mod
+func (ProxySource) Retractions(module string) ([]Retraction, error) {
+	data, err := deps.GetLatestModFile(module)
+	if err != nil {
+		return nil, err
+	}
+	f, err := modfile.ParseLax("go.mod", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod of %s: %w", module, err)
+	}
+	var retractions []Retraction
+	for _, r := range f.Retract {
+		retractions = append(retractions, Retraction{Low: r.Low, High: r.High, Rationale: r.Rationale})
+	}
+	return retractions, nil
+}
+
+// Vulnerabilities returns the OSV advisories affecting module at version
+func (ProxySource) Vulnerabilities(module, version string) ([]Vulnerability, error) {
+	query := map[string]interface{}{
+		"package": map[string]string{"name": module, "ecosystem": "Go"},
+		"version": strings.TrimPrefix(version, "v"),
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", osvQueryURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("Error closing response body: %v", err)
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from OSV: %s", resp.Status)
+	}
+	var data struct {
+		Vulns []Vulnerability `json:"vulns"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data.Vulns, nil
+}
@@ -0,0 +1,224 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/modfile"
+)
+
+// DefaultBaseBranch is the development branch assumed for repositories that do not set one
+const DefaultBaseBranch = "dev"
+
+// Repository describes a supported repository and its Go module
+type Repository struct {
+	Module string `json:"module"`
+	Slug   string `json:"slug"`
+	// BaseBranch receives pull requests (default "dev")
+	BaseBranch string `json:"baseBranch,omitempty"`
+	// ReleaseBranch is the branch releases are tagged from (default: BaseBranch)
+	ReleaseBranch string `json:"releaseBranch,omitempty"`
+}
+
+// Name returns the short repository name, e.g. "jfrog-cli-core"
+func (r Repository) Name() string {
+	if i := strings.LastIndex(r.Slug, "/"); i >= 0 {
+		return r.Slug[i+1:]
+	}
+	return r.Slug
+}
+
+// Base returns the base branch, applying the default
+func (r Repository) Base() string {
+	if r.BaseBranch == "" {
+		return DefaultBaseBranch
+	}
+	return r.BaseBranch
+}
+
+// Release returns the release branch, falling back to the base branch
+func (r Repository) Release() string {
+	if r.ReleaseBranch == "" {
+		return r.Base()
+	}
+	return r.ReleaseBranch
+}
+
+// defaultRepositories are the JFrog repositories jfrm manages out of the box. jfrog-cli ends
+// the release chain; it was not a managed dependency before the registry existed.
+var defaultRepositories = []Repository{
+	{Module: "github.com/jfrog/gofrog", Slug: "jfrog/gofrog"},
+	{Module: "github.com/jfrog/build-info-go", Slug: "jfrog/build-info-go"},
+	{Module: "github.com/jfrog/jfrog-client-go", Slug: "jfrog/jfrog-client-go"},
+	{Module: "github.com/jfrog/jfrog-cli-core/v2", Slug: "jfrog/jfrog-cli-core"},
+	{Module: "github.com/jfrog/jfrog-cli-artifactory", Slug: "jfrog/jfrog-cli-artifactory", BaseBranch: "main"},
+	{Module: "github.com/jfrog/jfrog-cli-security", Slug: "jfrog/jfrog-cli-security"},
+	{Module: "github.com/jfrog/jfrog-cli", Slug: "jfrog/jfrog-cli"},
+}
+
+// Registry is the set of supported repositories
+type Registry struct {
+	repos []Repository
+}
+
+// New creates a registry from the given repositories
+func New(repos []Repository) *Registry {
+	return &Registry{repos: append([]Repository(nil), repos...)}
+}
+
+// Default returns the built-in registry of JFrog repositories
+func Default() *Registry {
+	return New(defaultRepositories)
+}
+
+// With returns a copy of the registry where the given repositories replace entries with the
+// same module (fields left empty keep their previous value) or are appended
+func (r *Registry) With(extra []Repository) *Registry {
+	out := New(r.repos)
+	for _, e := range extra {
+		replaced := false
+		for i, existing := range out.repos {
+			if existing.Module != e.Module {
+				continue
+			}
+			if e.Slug != "" {
+				existing.Slug = e.Slug
+			}
+			if e.BaseBranch != "" {
+				existing.BaseBranch = e.BaseBranch
+			}
+			if e.ReleaseBranch != "" {
+				existing.ReleaseBranch = e.ReleaseBranch
+			}
+			out.repos[i] = existing
+			replaced = true
+			break
+		}
+		if !replaced {
+			out.repos = append(out.repos, e)
+		}
+	}
+	return out
+}
+
+// Validate checks that every repository has a module and a slug
+func (r *Registry) Validate() error {
+	for _, repo := range r.repos {
+		if repo.Module == "" || !strings.Contains(repo.Slug, "/") {
+			return fmt.Errorf("repository %q needs a module path and an owner/name slug", repo.Module+repo.Slug)
+		}
+	}
+	return nil
+}
+
+// Repositories returns the registered repositories in registration order
+func (r *Registry) Repositories() []Repository {
+	return append([]Repository(nil), r.repos...)
+}
+
+// ByModule looks up a repository by module path
+func (r *Registry) ByModule(module string) (Repository, bool) {
+	for _, repo := range r.repos {
+		if repo.Module == module {
+			return repo, true
+		}
+	}
+	return Repository{}, false
+}
+
+// BySlug looks up a repository by its owner/name slug (case-insensitive)
+func (r *Registry) BySlug(slug string) (Repository, bool) {
+	for _, repo := range r.repos {
+		if strings.EqualFold(repo.Slug, slug) {
+			return repo, true
+		}
+	}
+	return Repository{}, false
+}
+
+// Lookup finds a repository by module path, slug or short name
+func (r *Registry) Lookup(name string) (Repository, bool) {
+	if repo, ok := r.ByModule(name); ok {
+		return repo, true
+	}
+	if repo, ok := r.BySlug(name); ok {
+		return repo, true
+	}
+	for _, repo := range r.repos {
+		if repo.Name() == name {
+			return repo, true
+		}
+	}
+	return Repository{}, false
+}
+
+// IsManaged reports whether the module belongs to a registered repository
+func (r *Registry) IsManaged(module string) bool {
+	_, ok := r.ByModule(module)
+	return ok
+}
+
+// BaseBranch returns the base branch for a slug, or the default for unknown repositories
+func (r *Registry) BaseBranch(slug string) string {
+	if repo, ok := r.BySlug(slug); ok {
+		return repo.Base()
+	}
+	return DefaultBaseBranch
+}
+
+// ReleaseBranch returns the release branch for a slug, or the default for unknown repositories
+func (r *Registry) ReleaseBranch(slug string) string {
+	if repo, ok := r.BySlug(slug); ok {
+		return repo.Release()
+	}
+	return DefaultBaseBranch
+}
+
+// ModFetcher returns the go.mod content of a module's latest release
+type ModFetcher func(module string) ([]byte, error)
+
+// Dependencies reads each repository's go.mod and returns, per module, the registered
+// modules it requires (sorted)
+func (r *Registry) Dependencies(fetch ModFetcher) (map[string][]string, error) {
+	graph := make(map[string][]string, len(r.repos))
+	for _, repo := range r.repos {
+		data, err := fetch(repo.Module)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read go.mod of %s: %w", repo.Module, err)
+		}
+		f, err := modfile.ParseLax("go.mod", data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse go.mod of %s: %w", repo.Module, err)
+		}
+		var requires []string
+		for _, req := range f.Require {
+			if req.Mod.Path != repo.Module && r.IsManaged(req.Mod.Path) {
+				requires = append(requires, req.Mod.Path)
+			}
+		}
+		sort.Strings(requires)
+		graph[repo.Module] = requires
+	}
+	return graph, nil
+}
+
+var (
+	currentMu sync.RWMutex
+	current   = Default()
+)
+
+// Current returns the registry configured for this process
+func Current() *Registry {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// SetCurrent replaces the registry used by all commands in this process
+func SetCurrent(r *Registry) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = r
+}
@@ -0,0 +1,57 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	reg := Default().With([]Repository{
+		{Module: "github.com/jfrog/jfrog-client-go", ReleaseBranch: "master"},
+		{Module: "example.com/internal/tool", Slug: "acme/tool", BaseBranch: "main"},
+	})
+	client, ok := reg.BySlug("jfrog/jfrog-client-go")
+	if !ok || client.Base() != "dev" || client.Release() != "master" {
+		t.Fatalf("unexpected override: %+v", client)
+	}
+	if !reg.IsManaged("example.com/internal/tool") || reg.BaseBranch("acme/tool") != "main" {
+		t.Fatalf("expected configured repository to be registered")
+	}
+	if Default().IsManaged("example.com/internal/tool") {
+		t.Fatalf("With must not modify the original registry")
+	}
+	if reg.BaseBranch("jfrog/jfrog-cli-artifactory") != "main" || reg.BaseBranch("unknown/repo") != "dev" {
+		t.Fatalf("unexpected base branches")
+	}
+	if repo, ok := reg.Lookup("jfrog-cli-core"); !ok || repo.Module != "github.com/jfrog/jfrog-cli-core/v2" {
+		t.Fatalf("lookup by name failed: %+v", repo)
+	}
+	if err := Default().With([]Repository{{Module: "x"}}).Validate(); err == nil {
+		t.Fatalf("expected validation error for missing slug")
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	reg := New([]Repository{
+		{Module: "example.com/a", Slug: "o/a"},
+		{Module: "example.com/b", Slug: "o/b"},
+	})
+	mods := map[string]string{
+		"example.com/a": "module example.com/a\n\nrequire golang.org/x/mod v0.1.0\n",
+		"example.com/b": "module example.com/b\n\nrequire (\n\texample.com/a v1.0.0\n\tgolang.org/x/mod v0.1.0\n)\n",
+	}
+	graph, err := reg.Dependencies(func(module string) ([]byte, error) {
+		data, ok := mods[module]
+		if !ok {
+			return nil, fmt.Errorf("unknown module")
+		}
+		return []byte(data), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(graph["example.com/a"]) != 0 || strings.Join(graph["example.com/b"], ",") != "example.com/a" {
+		t.Fatalf("unexpected graph: %v", graph)
+	}
+}
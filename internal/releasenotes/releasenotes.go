@@ -0,0 +1,212 @@
+package releasenotes
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/version"
+)
+
+// Category groups pull requests in the release notes
+type Category struct {
+	Title string `json:"title"`
+	// Labels assigns a PR to this category when it carries any of them (case-insensitive)
+	Labels []string `json:"labels,omitempty"`
+	// CommitTypes assigns a PR by its Conventional Commit type (e.g. "feat")
+	CommitTypes []string `json:"commitTypes,omitempty"`
+	// Breaking assigns PRs marked breaking via "type!:" or a BREAKING CHANGE footer
+	Breaking bool `json:"breaking,omitempty"`
+}
+
+// DefaultCategories lists the sections of the release notes in display order.
+// A PR is placed in the first category it matches.
+var DefaultCategories = []Category{
+	{Title: "Breaking Changes", Labels: []string{"breaking change"}, Breaking: true},
+	{Title: "Features", Labels: []string{"new feature", "feature request"}, CommitTypes: []string{"feat"}},
+	{Title: "Improvements", Labels: []string{"improvement"}, CommitTypes: []string{"perf", "refactor"}},
+	{Title: "Bug Fixes", Labels: []string{"bug"}, CommitTypes: []string{"fix"}},
+	{Title: "Dependencies", Labels: []string{"dependencies"}, CommitTypes: []string{"deps"}},
+}
+
+// otherTitle is the section for PRs matching no category
+const otherTitle = "Other Changes"
+
+// Options controls how pull requests are grouped and filtered
+type Options struct {
+	Categories []Category
+	// ExcludeLabels drops PRs carrying any of these labels
+	ExcludeLabels []string
+	// ExcludeAuthors drops PRs by these authors; a leading "*" matches by suffix (e.g. "*[bot]")
+	ExcludeAuthors []string
+}
+
+// DefaultOptions returns the options used when nothing is configured
+func DefaultOptions() Options {
+	return Options{
+		Categories:    DefaultCategories,
+		ExcludeLabels: []string{"ignore for release"},
+	}
+}
+
+// Section is a titled group of pull requests
+type Section struct {
+	Title        string
+	PullRequests []github.PullRequest
+}
+
+// Notes is the structured content of a release's notes and the data passed to templates
+type Notes struct {
+	Version         string
+	PreviousVersion string
+	Date            time.Time
+	Sections        []Section
+	Dependencies    []deps.Update
+	Contributors    []string
+}
+
+// Build groups the pull requests into categories, appends the dependency updates and collects contributors
+func Build(ver, previous string, prs []github.PullRequest, updates []deps.Update, opts Options) Notes {
+	notes := Notes{Version: ver, PreviousVersion: previous, Date: time.Now(), Dependencies: updates}
+	grouped := make([][]github.PullRequest, len(opts.Categories)+1)
+	authors := make(map[string]bool)
+	for _, pr := range prs {
+		if opts.excludes(pr) {
+			continue
+		}
+		idx := len(opts.Categories)
+		for i, cat := range opts.Categories {
+			if cat.matches(pr) {
+				idx = i
+				break
+			}
+		}
+		grouped[idx] = append(grouped[idx], pr)
+		if pr.Author != "" {
+			authors[pr.Author] = true
+		}
+	}
+	for i, group := range grouped {
+		if len(group) == 0 {
+			continue
+		}
+		title := otherTitle
+		if i < len(opts.Categories) {
+			title = opts.Categories[i].Title
+		}
+		notes.Sections = append(notes.Sections, Section{Title: title, PullRequests: group})
+	}
+	for author := range authors {
+		notes.Contributors = append(notes.Contributors, author)
+	}
+	sort.Strings(notes.Contributors)
+	return notes
+}
+
+func (o Options) excludes(pr github.PullRequest) bool {
+	for _, label := range o.ExcludeLabels {
+		if pr.HasLabel(label) {
+			return true
+		}
+	}
+	for _, author := range o.ExcludeAuthors {
+		if suffix, ok := strings.CutPrefix(author, "*"); ok {
+			if strings.HasSuffix(pr.Author, suffix) {
+				return true
+			}
+		} else if strings.EqualFold(pr.Author, author) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Category) matches(pr github.PullRequest) bool {
+	for _, label := range c.Labels {
+		if pr.HasLabel(label) {
+			return true
+		}
+	}
+	commitType, breaking, ok := version.ParseConventionalTitle(pr.Title)
+	if c.Breaking && (breaking || version.HasBreakingFooter(pr.Body)) {
+		return true
+	}
+	if ok {
+		for _, t := range c.CommitTypes {
+			if strings.EqualFold(t, commitType) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DefaultTemplate is the built-in Markdown layout of the release notes
+const DefaultTemplate = `{{- if and (not .Sections) (not .Dependencies) -}}
+No notable changes.
+{{ end -}}
+{{- range .Sections -}}
+## {{ .Title }}
+
+{{ range .PullRequests -}}
+- {{ .Title }} (#{{ .Number }}){{ if .Author }} by @{{ .Author }}{{ end }}
+{{ end }}
+{{ end -}}
+{{- if .Dependencies -}}
+## Dependency Updates
+
+{{ range .Dependencies -}}
+- ` + "`{{ .Module }}`" + `: {{ .From }} → {{ .To }}
+{{ end }}
+{{ end -}}
+{{- if .Contributors -}}
+## Contributors
+
+{{ range .Contributors -}}
+- @{{ . }}
+{{ end -}}
+{{- end -}}
+`
+
+// Render writes the notes using the given text/template source; an empty source uses DefaultTemplate
+func (n Notes) Render(w io.Writer, tmpl string) error {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = DefaultTemplate
+	}
+	t, err := template.New("release-notes").Funcs(template.FuncMap{
+		"join":  strings.Join,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}).Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("invalid release notes template: %w", err)
+	}
+	return t.Execute(w, n)
+}
+
+// RenderFile renders the notes with the template stored at path (DefaultTemplate when path is empty)
+func (n Notes) RenderFile(w io.Writer, path string) error {
+	if path == "" {
+		return n.Render(w, "")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+	return n.Render(w, string(data))
+}
+
+// Markdown renders the notes with the default template
+func (n Notes) Markdown() string {
+	var b strings.Builder
+	if err := n.Render(&b, ""); err != nil {
+		return ""
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package releasenotes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/github"
+)
+
+func TestBuild(t *testing.T) {
+	prs := []github.PullRequest{
+		{Number: 1, Title: "Fix crash", Author: "alice", Labels: []string{"bug"}},
+		{Number: 2, Title: "feat: add command", Author: "bob"},
+		{Number: 3, Title: "feat!: drop flag", Author: "alice"},
+		{Number: 4, Title: "Update README", Author: "carol"},
+		{Number: 5, Title: "Bump x", Author: "dependabot[bot]"},
+		{Number: 6, Title: "Internal", Author: "dave", Labels: []string{"ignore for release"}},
+	}
+	opts := DefaultOptions()
+	opts.ExcludeAuthors = []string{"*[bot]"}
+	notes := Build("v1.3.0", "v1.2.0", prs, nil, opts)
+
+	var titles []string
+	for _, s := range notes.Sections {
+		titles = append(titles, s.Title)
+	}
+	if got := strings.Join(titles, "|"); got != "Breaking Changes|Features|Bug Fixes|Other Changes" {
+		t.Fatalf("unexpected sections: %s", got)
+	}
+	if got := strings.Join(notes.Contributors, ","); got != "alice,bob,carol" {
+		t.Fatalf("unexpected contributors: %s", got)
+	}
+	md := notes.Markdown()
+	if !strings.Contains(md, "- feat!: drop flag (#3) by @alice") {
+		t.Fatalf("missing breaking entry in:\n%s", md)
+	}
+}
+
+func TestRender(t *testing.T) {
+	notes := Build("v1.3.0", "v1.2.0", nil, []deps.Update{{Module: "github.com/jfrog/gofrog", From: "v1.7.5", To: "v1.7.6"}}, DefaultOptions())
+	md := notes.Markdown()
+	if !strings.Contains(md, "## Dependency Updates") || !strings.Contains(md, "`github.com/jfrog/gofrog`: v1.7.5 → v1.7.6") {
+		t.Fatalf("missing dependency section in:\n%s", md)
+	}
+	if strings.Contains(md, "No notable changes") {
+		t.Fatalf("unexpected empty marker in:\n%s", md)
+	}
+
+	var b strings.Builder
+	if err := notes.Render(&b, "{{ .PreviousVersion }}..{{ .Version }} {{ len .Dependencies }}"); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "v1.2.0..v1.3.0 1" {
+		t.Fatalf("unexpected custom render: %q", b.String())
+	}
+	if err := notes.Render(&b, "{{ .Missing"); err == nil {
+		t.Fatalf("expected template parse error")
+	}
+}
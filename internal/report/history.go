@@ -0,0 +1,213 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+)
+
+// snapshotTimeFormat names snapshot files so that they sort chronologically
+const snapshotTimeFormat = "20060102T150405Z"
+
+// Snapshot is a report saved in the history directory
+type Snapshot struct {
+	// Name is the file name of the snapshot within the history directory
+	Name string
+	*Report
+}
+
+// SaveSnapshot stores the report data as JSON in dir and returns the file path. Snapshots
+// taken within the same second get a numeric suffix instead of overwriting each other.
+func SaveSnapshot(dir string, r *Report) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	base := fmt.Sprintf("%s-%s", r.GeneratedAt.UTC().Format(snapshotTimeFormat), r.Kind)
+	for n := 1; ; n++ {
+		name := base + ".json"
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d.json", base, n)
+		}
+		path := filepath.Join(dir, name)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			_ = f.Close()
+			return "", err
+		}
+		return path, f.Close()
+	}
+}
+
+// LoadSnapshot reads a snapshot from path
+func LoadSnapshot(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	r := &Report{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	return Snapshot{Name: filepath.Base(path), Report: r}, nil
+}
+
+// LoadHistory reads every snapshot in dir, oldest first
+func LoadHistory(dir string) ([]Snapshot, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []Snapshot
+	for _, path := range paths {
+		s, err := LoadSnapshot(path)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].GeneratedAt.Before(snapshots[j].GeneratedAt)
+	})
+	return snapshots, nil
+}
+
+// Change is a dependency whose state differs between two snapshots
+type Change struct {
+	Module string `json:"module"`
+	// Before and After are the statuses in the older and newer snapshot; zero when the module is absent
+	Before deps.Status `json:"before"`
+	After  deps.Status `json:"after"`
+}
+
+// Diff compares two snapshots
+type Diff struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// NewUpdates were up to date (or not required) in the older snapshot and are outdated in the
+	// newer one, or moved to a newer version that is outdated again
+	NewUpdates []Change `json:"newUpdates"`
+	// Resolved were outdated in the older snapshot and are up to date or gone in the newer one
+	Resolved []Change `json:"resolved"`
+	// Drift stayed outdated while the latest version moved further ahead
+	Drift []Change `json:"drift"`
+	// Unknown could not be looked up in one snapshot and are outdated in the other, so whether
+	// they were resolved or newly outdated is not known
+	Unknown []Change `json:"unknown"`
+}
+
+// Compare reports what changed from the older snapshot to the newer one
+func Compare(from, to Snapshot) Diff {
+	d := Diff{From: from.Name, To: to.Name, NewUpdates: []Change{}, Resolved: []Change{}, Drift: []Change{}, Unknown: []Change{}}
+	before := make(map[string]deps.Status)
+	for _, s := range from.Dependencies {
+		before[s.Module] = s
+	}
+	after := make(map[string]deps.Status)
+	for _, s := range to.Dependencies {
+		after[s.Module] = s
+	}
+
+	modules := make(map[string]bool)
+	for m := range before {
+		modules[m] = true
+	}
+	for m := range after {
+		modules[m] = true
+	}
+	var names []string
+	for m := range modules {
+		names = append(names, m)
+	}
+	sort.Strings(names)
+
+	for _, m := range names {
+		b, a := before[m], after[m]
+		c := Change{Module: m, Before: b, After: a}
+		wasOutdated := b.Status == deps.StatusOutdated
+		isOutdated := a.Status == deps.StatusOutdated
+		switch {
+		case wasOutdated && (a.Status == deps.StatusUpToDate || a.Module == ""):
+			d.Resolved = append(d.Resolved, c)
+		case wasOutdated && a.Status == deps.StatusError, b.Status == deps.StatusError && isOutdated:
+			d.Unknown = append(d.Unknown, c)
+		case (b.Status == deps.StatusUpToDate || b.Module == "") && isOutdated:
+			d.NewUpdates = append(d.NewUpdates, c)
+		case wasOutdated && isOutdated && a.Latest != b.Latest:
+			if a.Current == b.Current {
+				d.Drift = append(d.Drift, c)
+			} else {
+				d.NewUpdates = append(d.NewUpdates, c)
+			}
+		}
+	}
+	return d
+}
+
+// Markdown renders the diff as Markdown
+func (d Diff) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Dependency Report Diff\n\n**From:** %s\n**To:** %s\n\n", d.From, d.To)
+	section := func(title string, changes []Change, line func(Change) string) {
+		fmt.Fprintf(&b, "## %s\n\n", title)
+		if len(changes) == 0 {
+			b.WriteString("None.\n\n")
+			return
+		}
+		for _, c := range changes {
+			fmt.Fprintf(&b, "- `%s`: %s\n", c.Module, line(c))
+		}
+		b.WriteString("\n")
+	}
+	section("New Updates", d.NewUpdates, func(c Change) string {
+		return fmt.Sprintf("%s → %s", c.After.Current, c.After.Latest)
+	})
+	section("Resolved", d.Resolved, func(c Change) string {
+		if c.After.Module == "" {
+			return fmt.Sprintf("no longer a dependency (was %s, latest %s)", c.Before.Current, c.Before.Latest)
+		}
+		return fmt.Sprintf("%s → %s", c.Before.Current, c.After.Current)
+	})
+	section("Drift", d.Drift, func(c Change) string {
+		return fmt.Sprintf("still on %s, latest moved %s → %s", c.After.Current, c.Before.Latest, c.After.Latest)
+	})
+	if len(d.Unknown) > 0 {
+		section("Lookup Failed", d.Unknown, func(c Change) string {
+			if c.Before.Status == deps.StatusError {
+				return fmt.Sprintf("now %s (latest %s), previously unknown: %s", c.After.Current, c.After.Latest, c.Before.Error)
+			}
+			return fmt.Sprintf("was %s (latest %s), now unknown: %s", c.Before.Current, c.Before.Latest, c.After.Error)
+		})
+	}
+	return b.String()
+}
+
+// Trend renders the snapshots as a Markdown table, one row per snapshot
+func Trend(snapshots []Snapshot) string {
+	var b strings.Builder
+	b.WriteString("| Date | Release | Dependencies | Outdated | Errors | Outdated Modules |\n")
+	b.WriteString("|------|---------|--------------|----------|--------|------------------|\n")
+	for _, s := range snapshots {
+		var outdated []string
+		for _, dep := range s.Outdated() {
+			outdated = append(outdated, fmt.Sprintf("%s (%s → %s)", dep.Module, dep.Current, dep.Latest))
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | %d | %d | %s |\n",
+			s.GeneratedAt.Format("2006-01-02 15:04"), s.LatestRelease, s.Summary.Total, s.Summary.Outdated, s.Summary.Errors, strings.Join(outdated, "<br>"))
+	}
+	return b.String()
+}
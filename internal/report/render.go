@@ -0,0 +1,163 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+)
+
+// Formats lists the supported report formats
+var Formats = []string{"markdown", "json", "html", "junit", "sarif"}
+
+// extensions maps each format to the file extension of its reports
+var extensions = map[string]string{
+	"markdown": ".md",
+	"json":     ".json",
+	"html":     ".html",
+	"junit":    ".xml",
+	"sarif":    ".sarif",
+}
+
+// Extension returns the file extension used for reports in format
+func Extension(format string) (string, error) {
+	ext, ok := extensions[format]
+	if !ok {
+		return "", fmt.Errorf("unsupported report format %q; expected one of %s", format, strings.Join(Formats, ", "))
+	}
+	return ext, nil
+}
+
+// Render writes the report in the given format. Markdown and HTML are rendered from
+// templates; templateDir may provide overrides named after TemplateName.
+func (r *Report) Render(w io.Writer, format, templateDir string) error {
+	switch format {
+	case "markdown":
+		return r.renderMarkdown(w, templateDir)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case "html":
+		return r.renderHTML(w, templateDir)
+	case "junit":
+		return r.writeJUnit(w)
+	case "sarif":
+		return r.writeSARIF(w)
+	}
+	_, err := Extension(format)
+	return err
+}
+
+// junitTestSuites is the JUnit XML document with one test case per dependency
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (r *Report) writeJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:      "jfrm dependencies: " + r.Repository,
+		Tests:     r.Summary.Total,
+		Failures:  r.Summary.Outdated,
+		Errors:    r.Summary.Errors,
+		Timestamp: r.GeneratedAt.Format("2006-01-02T15:04:05"),
+	}
+	for _, s := range r.Dependencies {
+		tc := junitTestCase{Name: s.Module, ClassName: r.Repository}
+		switch s.Status {
+		case deps.StatusOutdated:
+			msg := fmt.Sprintf("update available: %s → %s", s.Current, s.Latest)
+			tc.Failure = &junitProblem{Message: msg, Type: s.UpdateType, Text: msg}
+		case deps.StatusError:
+			tc.Error = &junitProblem{Message: s.Error, Type: "lookup", Text: s.Error}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// sarifRuleID identifies the outdated dependency rule in SARIF results
+const sarifRuleID = "outdated-dependency"
+
+func (r *Report) writeSARIF(w io.Writer) error {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type location struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+		} `json:"physicalLocation"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations"`
+	}
+	var loc location
+	loc.PhysicalLocation.ArtifactLocation.URI = "go.mod"
+	results := []result{}
+	for _, s := range r.Outdated() {
+		results = append(results, result{
+			RuleID:    sarifRuleID,
+			Level:     "warning",
+			Message:   message{Text: fmt.Sprintf("%s %s is outdated; latest is %s (%s update)", s.Module, s.Current, s.Latest, s.UpdateType)},
+			Locations: []location{loc},
+		})
+	}
+	doc := map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []interface{}{map[string]interface{}{
+			"tool": map[string]interface{}{"driver": map[string]interface{}{
+				"name":           "jfrm",
+				"informationUri": "https://github.com/bhanurp/jfrm",
+				"rules": []interface{}{map[string]interface{}{
+					"id":               sarifRuleID,
+					"shortDescription": message{Text: "A managed dependency has a newer version"},
+				}},
+			}},
+			"results": results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
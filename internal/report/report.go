@@ -8,120 +8,136 @@ import (
 	"time"
 
 	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/github"
 	"github.com/bhanurp/jfrm/internal/version"
 )
 
-// GenerateDryRunReport generates a dry-run report
-func GenerateDryRunReport(repo string, prs []string, tag string) error {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	report := fmt.Sprintf("# Dry-Run Report\n\n**Repository:** %s\n**Generated On:** %s\n\n", repo, timestamp)
-
-	dryRunReport := deps.GetDryRunReport()
-	if len(dryRunReport) == 0 {
-		report += "✅ All dependencies are already up to date!\n"
-	} else {
-		report += "### Dependencies that would be updated:\n\n"
-		for _, line := range dryRunReport {
-			report += line + "\n"
-		}
-	}
-
-	releaseType := version.DetermineReleaseType(prs)
-
-	if len(prs) > 0 {
-		report += "\n### Merged PRs since the latest release:\n\n"
-		for _, pr := range prs {
-			if strings.Contains(pr, ", ,") { // Check if PR has no labels
-				report += pr + " (No labels)\n"
-			} else {
-				report += pr + "\n"
-			}
-		}
-		report += fmt.Sprintf("\n### Decision on new release: %s\n", releaseType)
-		report += fmt.Sprintf("Next possible version: %s\n", version.GetNextVersion(tag, releaseType))
-	}
-
-	err := os.WriteFile("dry-run-report.md", []byte(report), 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write dry-run report: %w", err)
-	}
-	log.Println("✅ Dry-Run Report generated: dry-run-report.md")
+// Report kinds
+const (
+	KindDependency = "dependency"
+	KindDryRun     = "dry-run"
+)
 
-	// Clear the dry run report after generating
-	deps.ClearDryRunReport()
-	return nil
+// DryRunFile is the base name of the dry-run report; the extension follows the format
+const DryRunFile = "dry-run-report"
+
+// Report is the data shared by every report format
+type Report struct {
+	Kind          string    `json:"kind"`
+	Repository    string    `json:"repository"`
+	GeneratedAt   time.Time `json:"generatedAt"`
+	LatestRelease string    `json:"latestRelease"`
+	// LatestReleaseDate is when the latest release was published, zero when unknown
+	LatestReleaseDate time.Time `json:"latestReleaseDate"`
+	// Stale marks a report built from local caches in offline mode
+	Stale        bool                 `json:"stale"`
+	Summary      Summary              `json:"summary"`
+	Dependencies []deps.Status        `json:"dependencies"`
+	PullRequests []github.PullRequest `json:"pullRequests"`
+	Release      Release              `json:"release"`
 }
 
-// GenerateDependencyReport generates a comprehensive dependency report
-func GenerateDependencyReport(repo string, dependencies map[string]string, prs []string, tag string, outputFile string) error {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	report := fmt.Sprintf("# Dependency Report\n\n**Repository:** %s\n**Generated On:** %s\n**Current Version:** %s\n\n", repo, timestamp, tag)
+// Summary counts the dependencies by status
+type Summary struct {
+	Total    int `json:"total"`
+	Outdated int `json:"outdated"`
+	Errors   int `json:"errors"`
+}
 
-	// Dependency Status Section
-	report += "## Dependency Status\n\n"
-	report += "| Module | Current Version | Latest Version | Status |\n"
-	report += "|--------|----------------|----------------|--------|\n"
+// Release is the release analysis derived from the merged PRs
+type Release struct {
+	Type          string          `json:"type"`
+	Bump          string          `json:"bump"`
+	NextVersion   string          `json:"nextVersion"`
+	Justification []Justification `json:"justification"`
+	Skipped       int             `json:"skipped"`
+}
 
-	updatesAvailable := 0
-	for mod, currentVer := range dependencies {
-		if deps.IsAllowedDependency(mod) {
-			latestVer, err := deps.GetLatestModuleVersion(mod)
-			if err != nil {
-				report += fmt.Sprintf("| %s | %s | Error | ❌ Error |\n", mod, currentVer)
-				continue
-			}
+// Justification is a PR that determined the release type
+type Justification struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
 
-			status := "✅ Up to date"
-			if deps.IsNewerVersion(currentVer, latestVer) {
-				status = "🔄 Update available"
-				updatesAvailable++
-			}
-			report += fmt.Sprintf("| %s | %s | %s | %s |\n", mod, currentVer, latestVer, status)
+// New builds a report of the given kind from the dependency statuses and release analysis
+func New(kind, repo string, statuses []deps.Status, prs []github.PullRequest, tag, nextVersion string, decision version.Decision) *Report {
+	r := &Report{
+		Kind:          kind,
+		Repository:    repo,
+		GeneratedAt:   time.Now(),
+		LatestRelease: tag,
+		Summary:       Summary{Total: len(statuses), Outdated: deps.Outdated(statuses)},
+		Dependencies:  statuses,
+		PullRequests:  prs,
+		Release: Release{
+			Type:          version.ReleaseType(decision.Bump),
+			Bump:          decision.Bump.String(),
+			NextVersion:   nextVersion,
+			Justification: []Justification{},
+			Skipped:       len(decision.Skipped),
+		},
+	}
+	if r.Dependencies == nil {
+		r.Dependencies = []deps.Status{}
+	}
+	if r.PullRequests == nil {
+		r.PullRequests = []github.PullRequest{}
+	}
+	for _, s := range statuses {
+		if s.Status == deps.StatusError {
+			r.Summary.Errors++
 		}
+		r.Stale = r.Stale || s.Stale
 	}
+	for _, reason := range decision.Justification() {
+		r.Release.Justification = append(r.Release.Justification, Justification{Number: reason.PR.Number, Title: reason.PR.Title, Reason: reason.Source})
+	}
+	return r
+}
 
-	report += fmt.Sprintf("\n**Summary:** %d out of %d dependencies have updates available.\n\n", updatesAvailable, len(dependencies))
-
-	// Recent Activity Section
-	if len(prs) > 0 {
-		report += "## Recent Activity\n\n"
-		report += "### Merged PRs since the latest release:\n\n"
-		for _, pr := range prs {
-			if strings.Contains(pr, ", ,") {
-				report += "- " + pr + " (No labels)\n"
-			} else {
-				report += "- " + pr + "\n"
-			}
+// Outdated returns the dependencies with an update available
+func (r *Report) Outdated() []deps.Status {
+	var outdated []deps.Status
+	for _, s := range r.Dependencies {
+		if s.Status == deps.StatusOutdated {
+			outdated = append(outdated, s)
 		}
-
-		releaseType := version.DetermineReleaseType(prs)
-		nextVersion := version.GetNextVersion(tag, releaseType)
-		report += fmt.Sprintf("\n### Release Analysis\n")
-		report += fmt.Sprintf("- **Recommended release type:** %s\n", releaseType)
-		report += fmt.Sprintf("- **Next version:** %s\n", nextVersion)
-	} else {
-		report += "## Recent Activity\n\n"
-		report += "No merged PRs found since the latest release.\n"
 	}
+	return outdated
+}
 
-	// Recommendations Section
-	report += "\n## Recommendations\n\n"
-	if updatesAvailable > 0 {
-		report += "1. **Update Dependencies:** Consider updating the outdated dependencies to their latest versions.\n"
-		report += "2. **Run Tests:** After updating dependencies, ensure all tests pass.\n"
-		report += "3. **Review Changes:** Check for any breaking changes in the updated dependencies.\n"
-	} else {
-		report += "✅ All dependencies are up to date. No immediate action required.\n"
+// GenerateDryRunReport writes the dry-run report in the given format to outputFile,
+// or to dry-run-report.<ext> when outputFile is empty
+func GenerateDryRunReport(r *Report, format, outputFile, templateDir string) error {
+	if outputFile == "" {
+		ext, err := Extension(format)
+		if err != nil {
+			return err
+		}
+		outputFile = DryRunFile + ext
 	}
-
-	if len(prs) > 0 {
-		report += "4. **Consider Release:** Based on the merged PRs, consider creating a new release.\n"
+	if err := writeReport(r, format, outputFile, templateDir); err != nil {
+		return fmt.Errorf("failed to write dry-run report: %w", err)
 	}
+	log.Printf("✅ Dry-Run Report generated: %s", outputFile)
+	return nil
+}
 
-	err := os.WriteFile(outputFile, []byte(report), 0644)
-	if err != nil {
+// GenerateDependencyReport writes a comprehensive dependency report in the given format
+func GenerateDependencyReport(r *Report, format, outputFile, templateDir string) error {
+	if err := writeReport(r, format, outputFile, templateDir); err != nil {
 		return fmt.Errorf("failed to write dependency report: %w", err)
 	}
 	log.Printf("✅ Dependency Report generated: %s", outputFile)
 	return nil
 }
+
+// writeReport renders r into path
+func writeReport(r *Report, format, path, templateDir string) error {
+	var b strings.Builder
+	if err := r.Render(&b, format, templateDir); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
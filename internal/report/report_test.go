@@ -1,15 +1,25 @@
 package report
 
 import (
+	"encoding/json"
+	"encoding/xml"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/bhanurp/jfrm/internal/deps"
+	"github.com/bhanurp/jfrm/internal/github"
+	"github.com/bhanurp/jfrm/internal/version"
 )
 
 func TestGenerateDryRunReport(t *testing.T) {
 	repo := "owner/repo"
-	prs := []string{"PR #1, test, user, bug, 2025-07-17"}
+	prs := []github.PullRequest{{Number: 1, Title: "test", Author: "user", Labels: []string{"bug"}}}
 	tag := "v1.2.3"
-	if err := GenerateDryRunReport(repo, prs, tag); err != nil {
+	r := New(KindDryRun, repo, nil, prs, tag, "1.2.4", version.DefaultRules().Evaluate(prs))
+	if err := GenerateDryRunReport(r, "markdown", "", ""); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	// File should exist
@@ -19,3 +29,187 @@ func TestGenerateDryRunReport(t *testing.T) {
 	_ = os.Remove("dry-run-report.md")
 }
 
+func TestRenderJUnit(t *testing.T) {
+	statuses := []deps.Status{
+		{Module: "github.com/jfrog/gofrog", Current: "v1.7.5", Latest: "v1.7.6", Status: deps.StatusOutdated, UpdateType: "patch"},
+		{Module: "github.com/jfrog/jfrog-client-go", Current: "v1.50.0", Latest: "v1.50.0", Status: deps.StatusUpToDate},
+	}
+	r := New(KindDependency, "owner/repo", statuses, nil, "v1.2.3", "1.2.4", version.Decision{})
+	var b strings.Builder
+	if err := r.Render(&b, "junit", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc junitTestSuites
+	if err := xml.Unmarshal([]byte(b.String()), &doc); err != nil {
+		t.Fatalf("invalid JUnit XML: %v\n%s", err, b.String())
+	}
+	suite := doc.Suites[0]
+	if suite.Tests != 2 || suite.Failures != 1 || len(suite.Cases) != 2 {
+		t.Fatalf("unexpected suite: %+v", suite)
+	}
+	if suite.Cases[0].Failure == nil || suite.Cases[1].Failure != nil {
+		t.Fatalf("expected only the outdated dependency to fail: %+v", suite.Cases)
+	}
+
+	if err := r.Render(&b, "pdf", ""); err == nil {
+		t.Fatalf("expected error for unsupported format")
+	}
+}
+
+func TestRenderJSONHTMLAndSARIF(t *testing.T) {
+	statuses := []deps.Status{
+		{Module: "github.com/jfrog/gofrog", Current: "v1.7.5", Latest: "v1.7.6", Status: deps.StatusOutdated, UpdateType: "patch"},
+		{Module: "github.com/jfrog/jfrog-client-go", Current: "v1.47.0", Latest: "v1.50.0", Status: deps.StatusOutdated, UpdateType: "minor"},
+		{Module: "github.com/jfrog/build-info-go", Current: "v1.9.0", Latest: "v1.9.0", Status: deps.StatusUpToDate},
+		{Module: "github.com/jfrog/jfrog-cli-core", Current: "v2.50.0", Status: deps.StatusError, Error: "<proxy unavailable>"},
+	}
+	r := New(KindDependency, "owner/repo", statuses, nil, "v1.2.3", "1.2.4", version.Decision{})
+
+	var b strings.Builder
+	if err := r.Render(&b, "json", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal([]byte(b.String()), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, b.String())
+	}
+	if decoded.Repository != "owner/repo" || len(decoded.Dependencies) != 4 || decoded.Summary != (Summary{Total: 4, Outdated: 2, Errors: 1}) {
+		t.Fatalf("unexpected JSON report: %+v", decoded)
+	}
+
+	b.Reset()
+	if err := r.Render(&b, "html", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html := b.String()
+	if strings.Count(html, `<td class="outdated">`) != 2 || !strings.Contains(html, "github.com/jfrog/build-info-go") {
+		t.Fatalf("expected a row per dependency with two outdated:\n%s", html)
+	}
+	if strings.Contains(html, "<proxy unavailable>") || !strings.Contains(html, "&lt;proxy unavailable&gt;") {
+		t.Fatalf("expected lookup errors to be HTML-escaped:\n%s", html)
+	}
+
+	b.Reset()
+	if err := r.Render(&b, "sarif", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var sarif struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(b.String()), &sarif); err != nil {
+		t.Fatalf("invalid SARIF: %v\n%s", err, b.String())
+	}
+	if sarif.Version != "2.1.0" || len(sarif.Runs) != 1 || len(sarif.Runs[0].Results) != 2 {
+		t.Fatalf("expected one SARIF result per outdated dependency: %s", b.String())
+	}
+	for i, module := range []string{"github.com/jfrog/gofrog", "github.com/jfrog/jfrog-client-go"} {
+		res := sarif.Runs[0].Results[i]
+		if res.RuleID != sarifRuleID || !strings.HasPrefix(res.Message.Text, module+" ") {
+			t.Fatalf("unexpected result %d: %+v", i, res)
+		}
+	}
+}
+
+func TestRenderTemplateOverride(t *testing.T) {
+	dir := t.TempDir()
+	name, err := TemplateName(KindDependency, "markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := "{{.Repository}} {{.LatestRelease}}{{range .Dependencies}} {{icon .Status}} {{upper .Module}}{{end}}"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	statuses := []deps.Status{{Module: "github.com/jfrog/gofrog", Current: "v1.7.5", Latest: "v1.7.6", Status: deps.StatusOutdated}}
+	r := New(KindDependency, "owner/repo", statuses, nil, "v1.2.3", "1.2.4", version.Decision{})
+
+	var b strings.Builder
+	if err := r.Render(&b, "markdown", dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.String() != "owner/repo v1.2.3 🔄 GITHUB.COM/JFROG/GOFROG" {
+		t.Fatalf("override not used: %q", b.String())
+	}
+
+	// Kinds without an override fall back to the built-in template
+	b.Reset()
+	r.Kind = KindDryRun
+	if err := r.Render(&b, "markdown", dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(b.String(), "# Dry-Run Report") {
+		t.Fatalf("expected built-in dry-run template, got %q", b.String())
+	}
+}
+
+func TestHistoryCompare(t *testing.T) {
+	dir := t.TempDir()
+	older := New(KindDependency, "owner/repo", []deps.Status{
+		{Module: "a", Current: "v1.0.0", Latest: "v1.1.0", Status: deps.StatusOutdated},
+		{Module: "b", Current: "v1.0.0", Latest: "v1.1.0", Status: deps.StatusOutdated},
+		{Module: "c", Current: "v1.0.0", Latest: "v1.0.0", Status: deps.StatusUpToDate},
+		{Module: "d", Current: "v1.2.0", Latest: "v1.3.0", Status: deps.StatusOutdated},
+		{Module: "e", Current: "v1.0.0", Status: deps.StatusError, Error: "proxy unavailable"},
+	}, nil, "v1.0.0", "1.0.1", version.Decision{})
+	newer := New(KindDependency, "owner/repo", []deps.Status{
+		{Module: "a", Current: "v1.1.0", Latest: "v1.1.0", Status: deps.StatusUpToDate},
+		{Module: "b", Current: "v1.0.0", Latest: "v1.2.0", Status: deps.StatusOutdated},
+		{Module: "c", Current: "v1.0.0", Latest: "v1.0.1", Status: deps.StatusOutdated},
+		{Module: "d", Current: "v1.2.0", Status: deps.StatusError, Error: "proxy unavailable"},
+		{Module: "e", Current: "v1.0.0", Latest: "v1.1.0", Status: deps.StatusOutdated},
+	}, nil, "v1.0.1", "1.0.2", version.Decision{})
+	newer.GeneratedAt = older.GeneratedAt.Add(24 * time.Hour)
+	for _, r := range []*Report{newer, older} {
+		if _, err := SaveSnapshot(dir, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := LoadHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].LatestRelease != "v1.0.0" {
+		t.Fatalf("expected two snapshots, oldest first: %+v", history)
+	}
+	d := Compare(history[0], history[1])
+	if len(d.NewUpdates) != 1 || d.NewUpdates[0].Module != "c" {
+		t.Fatalf("unexpected new updates: %+v", d.NewUpdates)
+	}
+	if len(d.Resolved) != 1 || d.Resolved[0].Module != "a" {
+		t.Fatalf("unexpected resolved: %+v", d.Resolved)
+	}
+	if len(d.Drift) != 1 || d.Drift[0].Module != "b" {
+		t.Fatalf("unexpected drift: %+v", d.Drift)
+	}
+	if len(d.Unknown) != 2 || d.Unknown[0].Module != "d" || d.Unknown[1].Module != "e" {
+		t.Fatalf("expected failed lookups to be reported apart from resolved and new updates: %+v", d.Unknown)
+	}
+	if rows := strings.Count(Trend(history), "\n"); rows != 4 {
+		t.Fatalf("expected header and two rows, got:\n%s", Trend(history))
+	}
+
+	// Snapshots taken within the same second must not overwrite each other
+	first, err := SaveSnapshot(dir, newer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SaveSnapshot(dir, newer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatalf("expected distinct snapshot files, got %s twice", first)
+	}
+	if history, err = LoadHistory(dir); err != nil || len(history) != 4 {
+		t.Fatalf("expected four snapshots, got %d (%v)", len(history), err)
+	}
+}
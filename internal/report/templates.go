@@ -0,0 +1,102 @@
+package report
+
+import (
+	"embed"
+	"errors"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// funcs are the helper functions available to report templates
+var funcs = map[string]interface{}{
+	"date": func(t time.Time) string { return t.Format("2006-01-02 15:04:05") },
+	"formatTime": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"add":   func(a, b int) int { return a + b },
+	"icon": func(status string) string {
+		switch status {
+		case "outdated":
+			return "🔄"
+		case "error":
+			return "❌"
+		}
+		return "✅"
+	},
+}
+
+// TemplateName returns the file name of the template for a report kind and format,
+// e.g. dependency.md.tmpl; a file with this name in the template directory overrides the default
+func TemplateName(kind, format string) (string, error) {
+	ext, err := Extension(format)
+	if err != nil {
+		return "", err
+	}
+	return kind + ext + ".tmpl", nil
+}
+
+// overrideTemplate reads the named template from dir, returning nil when dir does not provide it
+func overrideTemplate(dir, name string) ([]byte, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// renderMarkdown executes the Markdown template for the report kind
+func (r *Report) renderMarkdown(w io.Writer, templateDir string) error {
+	name, _ := TemplateName(r.Kind, "markdown")
+	tmpl := template.New(name).Funcs(funcs)
+	tmpl, err := tmpl.ParseFS(builtinTemplates, "templates/justification.tmpl")
+	if err != nil {
+		return err
+	}
+	data, err := overrideTemplate(templateDir, name)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		if data, err = builtinTemplates.ReadFile("templates/" + name); err != nil {
+			return err
+		}
+	}
+	if _, err := tmpl.Parse(string(data)); err != nil {
+		return err
+	}
+	return tmpl.Execute(w, r)
+}
+
+// renderHTML executes the HTML template for the report kind
+func (r *Report) renderHTML(w io.Writer, templateDir string) error {
+	name, _ := TemplateName(r.Kind, "html")
+	data, err := overrideTemplate(templateDir, name)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		if data, err = builtinTemplates.ReadFile("templates/report.html.tmpl"); err != nil {
+			return err
+		}
+	}
+	tmpl, err := htmltemplate.New(name).Funcs(funcs).Parse(string(data))
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, r)
+}
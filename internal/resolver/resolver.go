@@ -0,0 +1,148 @@
+package resolver
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults used when the resolver is created without explicit limits
+const (
+	DefaultParallelism = 8
+	DefaultTimeout     = 10 * time.Second
+)
+
+// LookupFunc resolves the latest version of a module and when it was published
+type LookupFunc func(ctx context.Context, module string) (string, time.Time, error)
+
+// Result is the resolved latest version of a module
+type Result struct {
+	Module    string
+	Version   string
+	Published time.Time
+	Err       error
+}
+
+// entry memoizes one lookup; done is closed once the result is available
+type entry struct {
+	done   chan struct{}
+	result Result
+}
+
+// Resolver resolves latest module versions concurrently and remembers the results,
+// so each module is looked up at most once per process
+type Resolver struct {
+	lookup      LookupFunc
+	parallelism int
+	timeout     time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// New creates a resolver running at most parallelism lookups at a time, each bounded by timeout.
+// Non-positive values select the defaults.
+func New(lookup LookupFunc, parallelism int, timeout time.Duration) *Resolver {
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Resolver{lookup: lookup, parallelism: parallelism, timeout: timeout, entries: make(map[string]*entry)}
+}
+
+// Resolve looks up the latest version of every module concurrently and returns the
+// results sorted by module path. Lookups still pending when ctx is cancelled fail with its error.
+func (r *Resolver) Resolve(ctx context.Context, modules []string) []Result {
+	sem := make(chan struct{}, r.parallelism)
+	results := make([]Result, len(modules))
+	var wg sync.WaitGroup
+	for i, mod := range modules {
+		wg.Add(1)
+		go func(i int, mod string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+				results[i] = r.get(ctx, mod)
+			case <-ctx.Done():
+				results[i] = Result{Module: mod, Err: ctx.Err()}
+			}
+		}(i, mod)
+	}
+	wg.Wait()
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Module < results[j].Module })
+	return results
+}
+
+// Latest returns the latest version of a single module
+func (r *Resolver) Latest(ctx context.Context, module string) (string, time.Time, error) {
+	res := r.get(ctx, module)
+	return res.Version, res.Published, res.Err
+}
+
+// LatestFunc adapts the resolver to the func(module) (version, error) shape used by deps.Check
+func (r *Resolver) LatestFunc(ctx context.Context) func(string) (string, error) {
+	return func(module string) (string, error) {
+		v, _, err := r.Latest(ctx, module)
+		return v, err
+	}
+}
+
+// get returns the memoized result for module, performing the lookup on first use.
+// Lookups interrupted by the caller's cancellation are forgotten so a later call can retry.
+func (r *Resolver) get(ctx context.Context, module string) Result {
+	r.mu.Lock()
+	e, ok := r.entries[module]
+	if !ok {
+		e = &entry{done: make(chan struct{})}
+		r.entries[module] = e
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		e.result = r.do(ctx, module)
+		if ctx.Err() != nil {
+			r.mu.Lock()
+			delete(r.entries, module)
+			r.mu.Unlock()
+		}
+		close(e.done)
+		return e.result
+	}
+	select {
+	case <-e.done:
+		return e.result
+	case <-ctx.Done():
+		return Result{Module: module, Err: ctx.Err()}
+	}
+}
+
+// do performs a single lookup bounded by the per-request timeout
+func (r *Resolver) do(ctx context.Context, module string) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	v, published, err := r.lookup(ctx, module)
+	return Result{Module: module, Version: v, Published: published, Err: err}
+}
+
+var (
+	sharedMu sync.RWMutex
+	shared   *Resolver
+)
+
+// Shared returns the resolver shared by all commands in this process
+func Shared() *Resolver {
+	sharedMu.RLock()
+	defer sharedMu.RUnlock()
+	return shared
+}
+
+// SetShared installs the resolver returned by Shared
+func SetShared(r *Resolver) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	shared = r
+}
@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResolveConcurrentlyInOrder(t *testing.T) {
+	var calls, running, peak int32
+	lookup := func(ctx context.Context, module string) (string, time.Time, error) {
+		atomic.AddInt32(&calls, 1)
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if module == "bad" {
+			return "", time.Time{}, errors.New("boom")
+		}
+		return "v1.0.0-" + module, time.Time{}, nil
+	}
+	r := New(lookup, 2, time.Second)
+	modules := []string{"d", "bad", "a", "c", "b"}
+
+	results := r.Resolve(context.Background(), modules)
+	want := []string{"a", "b", "bad", "c", "d"}
+	for i, res := range results {
+		if res.Module != want[i] {
+			t.Fatalf("result %d is %s, want %s", i, res.Module, want[i])
+		}
+	}
+	if results[2].Err == nil || results[0].Version != "v1.0.0-a" {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if peak > 2 {
+		t.Fatalf("parallelism limit exceeded: %d lookups at once", peak)
+	}
+
+	// Results are remembered for the rest of the process
+	if v, _, err := r.Latest(context.Background(), "c"); err != nil || v != "v1.0.0-c" {
+		t.Fatalf("unexpected latest: %s %v", v, err)
+	}
+	if calls != int32(len(modules)) {
+		t.Fatalf("expected %d lookups, got %d", len(modules), calls)
+	}
+}
+
+func TestResolveTimeoutAndCancel(t *testing.T) {
+	lookup := func(ctx context.Context, module string) (string, time.Time, error) {
+		<-ctx.Done()
+		return "", time.Time{}, ctx.Err()
+	}
+	r := New(lookup, 1, 20*time.Millisecond)
+	if _, _, err := r.Latest(context.Background(), "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected per-request timeout, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, res := range New(lookup, 1, time.Minute).Resolve(ctx, []string{"a", "b"}) {
+		if !errors.Is(res.Err, context.Canceled) {
+			t.Fatalf("expected cancellation for %s, got %v", res.Module, res.Err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// PreReleaseChannels lists the supported pre-release identifiers in precedence order
+var PreReleaseChannels = []string{"alpha", "beta", "rc"}
+
+// Plan describes how the next version is derived from the latest tag
+type Plan struct {
+	Bump Bump
+	// PreRelease is the channel ("alpha", "beta" or "rc") to cut; empty plans a final release
+	PreRelease string
+	// Promote turns the latest pre-release into its final version
+	Promote bool
+}
+
+// Validate checks the plan for unsupported channels and conflicting options
+func (p Plan) Validate() error {
+	if p.PreRelease != "" && channelRank(p.PreRelease) < 0 {
+		return fmt.Errorf("unsupported pre-release %q; expected one of %s", p.PreRelease, strings.Join(PreReleaseChannels, ", "))
+	}
+	if p.Promote && p.PreRelease != "" {
+		return fmt.Errorf("cannot promote and cut a pre-release at the same time")
+	}
+	return nil
+}
+
+// Next computes the version following tag. When tag is itself a pre-release, its
+// core version is reused as long as it already covers the requested bump, so
+// v1.3.0-rc.1 is followed by v1.3.0-rc.2 (or v1.3.0 for a final release).
+func (p Plan) Next(tag string) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	v, err := semver.ParseTolerant(tag)
+	if err != nil {
+		return "", fmt.Errorf("invalid version %q: %w", tag, err)
+	}
+	bump := p.Bump
+	if bump == BumpNone {
+		bump = BumpPatch
+	}
+
+	if p.Promote {
+		if len(v.Pre) == 0 {
+			return "", fmt.Errorf("%s is not a pre-release; nothing to promote", tag)
+		}
+		return finalOf(v).String(), nil
+	}
+
+	core := finalOf(v)
+	channel, number := parsePreRelease(v)
+	if len(v.Pre) == 0 || bump > coveredBump(core) {
+		core = applyBump(core, bump)
+		channel, number = "", 0
+	}
+
+	if p.PreRelease == "" {
+		return core.String(), nil
+	}
+	switch {
+	case channel == p.PreRelease:
+		number++
+	case channel != "" && channelRank(p.PreRelease) < channelRank(channel):
+		return "", fmt.Errorf("cannot go back from %s to %s on %s", channel, p.PreRelease, core)
+	default:
+		number = 1
+	}
+	pre, err := semver.NewPRVersion(p.PreRelease)
+	if err != nil {
+		return "", err
+	}
+	core.Pre = []semver.PRVersion{pre, {VersionNum: uint64(number), IsNum: true}}
+	return core.String(), nil
+}
+
+// IsPreRelease reports whether tag carries a pre-release suffix
+func IsPreRelease(tag string) bool {
+	v, err := semver.ParseTolerant(tag)
+	return err == nil && len(v.Pre) > 0
+}
+
+func finalOf(v semver.Version) semver.Version {
+	return semver.Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+func applyBump(v semver.Version, b Bump) semver.Version {
+	switch b {
+	case BumpMajor:
+		return semver.Version{Major: v.Major + 1}
+	case BumpMinor:
+		return semver.Version{Major: v.Major, Minor: v.Minor + 1}
+	}
+	return semver.Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// coveredBump returns the largest bump a pre-release of core already represents
+func coveredBump(core semver.Version) Bump {
+	switch {
+	case core.Minor == 0 && core.Patch == 0:
+		return BumpMajor
+	case core.Patch == 0:
+		return BumpMinor
+	}
+	return BumpPatch
+}
+
+// parsePreRelease extracts "<channel>.<n>" from v; unknown suffixes yield no channel
+func parsePreRelease(v semver.Version) (string, int) {
+	if len(v.Pre) == 0 || v.Pre[0].IsNum || channelRank(v.Pre[0].VersionStr) < 0 {
+		return "", 0
+	}
+	if len(v.Pre) > 1 && v.Pre[1].IsNum {
+		return v.Pre[0].VersionStr, int(v.Pre[1].VersionNum)
+	}
+	return v.Pre[0].VersionStr, 0
+}
+
+func channelRank(channel string) int {
+	for i, c := range PreReleaseChannels {
+		if c == channel {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,193 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bhanurp/jfrm/internal/github"
+)
+
+// Bump is the kind of semantic version increment a set of changes requires
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+// String returns the lower-case name of the bump
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// ParseBump converts "major", "minor", "patch" or "none" into a Bump
+func ParseBump(s string) (Bump, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "major":
+		return BumpMajor, nil
+	case "minor":
+		return BumpMinor, nil
+	case "patch":
+		return BumpPatch, nil
+	case "none", "":
+		return BumpNone, nil
+	}
+	return BumpNone, fmt.Errorf("unknown bump %q; expected major, minor, patch or none", s)
+}
+
+// Rules configures how pull requests are mapped to a release bump
+type Rules struct {
+	// Labels maps a PR label (case-insensitive) to the bump it implies
+	Labels map[string]Bump
+	// SkipLabels excludes a PR from release planning entirely
+	SkipLabels []string
+	// ConventionalCommits enables parsing of "type(scope)!: subject" titles and BREAKING CHANGE footers
+	ConventionalCommits bool
+	// CommitTypes maps a Conventional Commit type to the bump it implies
+	CommitTypes map[string]Bump
+	// Default is the bump applied to a PR that matches no label or commit type
+	Default Bump
+}
+
+// DefaultRules returns the rules used when no configuration is provided
+func DefaultRules() Rules {
+	return Rules{
+		Labels: map[string]Bump{
+			"breaking change": BumpMajor,
+			"new feature":     BumpMinor,
+			"feature request": BumpMinor,
+			"improvement":     BumpPatch,
+			"bug":             BumpPatch,
+		},
+		SkipLabels:          []string{"ignore for release"},
+		ConventionalCommits: true,
+		CommitTypes: map[string]Bump{
+			"feat": BumpMinor,
+			"fix":  BumpPatch,
+			"perf": BumpPatch,
+		},
+		Default: BumpPatch,
+	}
+}
+
+// Reason records why a pull request contributes a given bump
+type Reason struct {
+	PR     github.PullRequest
+	Bump   Bump
+	Source string
+}
+
+// Decision is the outcome of evaluating rules against a set of pull requests
+type Decision struct {
+	Bump Bump
+	// Changes lists every PR taken into account together with the bump it implies
+	Changes []Reason
+	// Skipped lists PRs excluded from release planning
+	Skipped []github.PullRequest
+}
+
+// Justification returns the changes that determined the final bump
+func (d Decision) Justification() []Reason {
+	var out []Reason
+	for _, r := range d.Changes {
+		if r.Bump == d.Bump {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// conventionalTitle matches "type(scope)!: subject"
+var conventionalTitle = regexp.MustCompile(`^(\w+)(?:\([^)]*\))?(!)?:\s`)
+
+// breakingFooter matches a BREAKING CHANGE footer at the start of a body line
+var breakingFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:`)
+
+// Evaluate determines the bump required by the given pull requests
+func (r Rules) Evaluate(prs []github.PullRequest) Decision {
+	var d Decision
+	for _, pr := range prs {
+		if r.skips(pr) {
+			d.Skipped = append(d.Skipped, pr)
+			continue
+		}
+		reason := r.classify(pr)
+		d.Changes = append(d.Changes, reason)
+		if reason.Bump > d.Bump {
+			d.Bump = reason.Bump
+		}
+	}
+	sort.SliceStable(d.Changes, func(i, j int) bool { return d.Changes[i].Bump > d.Changes[j].Bump })
+	return d
+}
+
+func (r Rules) skips(pr github.PullRequest) bool {
+	for _, label := range r.SkipLabels {
+		if pr.HasLabel(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// classify returns the highest bump implied by a single pull request
+func (r Rules) classify(pr github.PullRequest) Reason {
+	reason := Reason{PR: pr, Bump: r.Default, Source: "default"}
+	matched := false
+	consider := func(b Bump, source string) {
+		if !matched || b > reason.Bump {
+			reason.Bump, reason.Source = b, source
+		}
+		matched = true
+	}
+
+	for _, label := range pr.Labels {
+		for name, b := range r.Labels {
+			if strings.EqualFold(label, name) {
+				consider(b, fmt.Sprintf("label %q", label))
+			}
+		}
+	}
+
+	if r.ConventionalCommits {
+		if commitType, breaking, ok := ParseConventionalTitle(pr.Title); ok {
+			if breaking {
+				consider(BumpMajor, fmt.Sprintf("conventional commit %q", commitType+"!"))
+			} else if b, ok := r.CommitTypes[commitType]; ok {
+				consider(b, fmt.Sprintf("conventional commit %q", commitType))
+			}
+		}
+		if HasBreakingFooter(pr.Body) {
+			consider(BumpMajor, "BREAKING CHANGE footer")
+		}
+	}
+	return reason
+}
+
+// ParseConventionalTitle extracts the lower-cased type and breaking marker from a
+// "type(scope)!: subject" title; ok is false when the title is not conventional
+func ParseConventionalTitle(title string) (commitType string, breaking bool, ok bool) {
+	m := conventionalTitle.FindStringSubmatch(strings.TrimSpace(title))
+	if m == nil {
+		return "", false, false
+	}
+	return strings.ToLower(m[1]), m[2] == "!", true
+}
+
+// HasBreakingFooter reports whether a PR body contains a BREAKING CHANGE footer
+func HasBreakingFooter(body string) bool {
+	return breakingFooter.MatchString(body)
+}
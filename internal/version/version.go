@@ -3,9 +3,22 @@ package version
 import (
 	"strings"
 
+	"github.com/bhanurp/jfrm/internal/github"
 	"github.com/blang/semver/v4"
 )
 
+// IncrementMajorVersion increments the major version and resets minor and patch to 0
+func IncrementMajorVersion(tag string) string {
+	v, err := semver.ParseTolerant(tag)
+	if err != nil {
+		return ""
+	}
+	v.Major++
+	v.Minor = 0
+	v.Patch = 0
+	return v.String()
+}
+
 // IncrementMinorVersion increments the minor version and resets patch to 0
 func IncrementMinorVersion(tag string) string {
 	v, err := semver.ParseTolerant(tag)
@@ -27,22 +40,52 @@ func IncrementPatchVersion(version string) string {
 	return v.String()
 }
 
-// GetNextVersion determines the next version based on release type
+// GetNextVersion determines the next final version based on release type
 func GetNextVersion(tag string, releaseType string) string {
-	if releaseType == "next patch" {
-		return IncrementPatchVersion(tag)
+	bump := BumpMinor
+	switch releaseType {
+	case "next major":
+		bump = BumpMajor
+	case "next patch":
+		bump = BumpPatch
+	}
+	next, err := Plan{Bump: bump}.Next(tag)
+	if err != nil {
+		return ""
+	}
+	return next
+}
+
+// ReleaseType converts a bump into the "next <bump>" form used in reports.
+// A release is only planned when something changed, so BumpNone maps to a patch.
+func ReleaseType(b Bump) string {
+	if b == BumpNone {
+		b = BumpPatch
 	}
-	return IncrementMinorVersion(tag)
+	return "next " + b.String()
 }
 
-// DetermineReleaseType determines the release type based on PR content
-func DetermineReleaseType(prs []string) string {
-	releaseType := "next patch"
-	for _, pr := range prs {
-		if strings.Contains(pr, "new feature") || strings.Contains(pr, "feature request") {
-			releaseType = "next minor"
-			break
+// DetermineReleaseType determines the release type based on PR labels and titles using the default rules
+func DetermineReleaseType(prs []github.PullRequest) string {
+	return ReleaseType(DefaultRules().Evaluate(prs).Bump)
+}
+
+// Latest returns the tag with the highest semantic version among the tags made of prefix
+// followed by a version, skipping pre-releases unless preReleases is set. ok is false when
+// no tag qualifies.
+func Latest(tags []string, prefix string, preReleases bool) (latest string, ok bool) {
+	var best semver.Version
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+		v, err := semver.Parse(strings.TrimPrefix(tag, prefix))
+		if err != nil || (len(v.Pre) > 0 && !preReleases) {
+			continue
+		}
+		if !ok || v.GT(best) {
+			latest, best, ok = tag, v, true
 		}
 	}
-	return releaseType
+	return latest, ok
 }
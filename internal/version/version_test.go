@@ -1,6 +1,10 @@
 package version
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/bhanurp/jfrm/internal/github"
+)
 
 func TestIncrementPatchVersion(t *testing.T) {
 	if got := IncrementPatchVersion("1.2.3"); got != "1.2.4" {
@@ -23,3 +27,98 @@ func TestGetNextVersion(t *testing.T) {
 	}
 }
 
+func TestGetNextVersionMajor(t *testing.T) {
+	if got := GetNextVersion("v1.2.3", "next major"); got != "2.0.0" {
+		t.Fatalf("expected 2.0.0, got %s", got)
+	}
+}
+
+func TestRulesEvaluate(t *testing.T) {
+	rules := DefaultRules()
+	cases := []struct {
+		name string
+		prs  []github.PullRequest
+		want Bump
+	}{
+		{"no changes", nil, BumpNone},
+		{"bug label", []github.PullRequest{{Number: 1, Labels: []string{"bug"}}}, BumpPatch},
+		{"feature label", []github.PullRequest{{Number: 1, Labels: []string{"bug"}}, {Number: 2, Labels: []string{"New Feature"}}}, BumpMinor},
+		{"breaking label", []github.PullRequest{{Number: 1, Labels: []string{"breaking change"}}}, BumpMajor},
+		{"feat title", []github.PullRequest{{Number: 1, Title: "feat(cli): add flag"}}, BumpMinor},
+		{"bang title", []github.PullRequest{{Number: 1, Title: "fix!: drop old API"}}, BumpMajor},
+		{"breaking footer", []github.PullRequest{{Number: 1, Title: "fix: x", Body: "details\n\nBREAKING CHANGE: removed Y"}}, BumpMajor},
+		{"skipped", []github.PullRequest{{Number: 1, Labels: []string{"new feature", "ignore for release"}}}, BumpNone},
+	}
+	for _, tc := range cases {
+		if got := rules.Evaluate(tc.prs).Bump; got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestDecisionJustification(t *testing.T) {
+	prs := []github.PullRequest{
+		{Number: 1, Labels: []string{"bug"}},
+		{Number: 2, Title: "feat: new command"},
+	}
+	d := DefaultRules().Evaluate(prs)
+	just := d.Justification()
+	if len(just) != 1 || just[0].PR.Number != 2 {
+		t.Fatalf("expected PR #2 to justify the bump, got %+v", just)
+	}
+	if DetermineReleaseType(prs) != "next minor" {
+		t.Fatalf("expected next minor, got %s", DetermineReleaseType(prs))
+	}
+}
+
+func TestPlanNext(t *testing.T) {
+	cases := []struct {
+		tag  string
+		plan Plan
+		want string
+	}{
+		{"v2.59.1", Plan{Bump: BumpMinor, PreRelease: "rc"}, "2.60.0-rc.1"},
+		{"v2.60.0-rc.1", Plan{Bump: BumpPatch, PreRelease: "rc"}, "2.60.0-rc.2"},
+		{"v2.60.0-rc.2", Plan{Bump: BumpMinor, PreRelease: "rc"}, "2.60.0-rc.3"},
+		{"v2.60.0-beta.3", Plan{Bump: BumpPatch, PreRelease: "rc"}, "2.60.0-rc.1"},
+		{"v2.60.0-rc.1", Plan{Bump: BumpMajor, PreRelease: "rc"}, "3.0.0-rc.1"},
+		{"v2.60.0-rc.2", Plan{Bump: BumpPatch}, "2.60.0"},
+		{"v2.60.0-rc.2", Plan{Promote: true}, "2.60.0"},
+		{"v1.2.3", Plan{Bump: BumpNone}, "1.2.4"},
+	}
+	for _, tc := range cases {
+		got, err := tc.plan.Next(tc.tag)
+		if err != nil {
+			t.Fatalf("%s %+v: unexpected error: %v", tc.tag, tc.plan, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s %+v: expected %s, got %s", tc.tag, tc.plan, tc.want, got)
+		}
+	}
+
+	if _, err := (Plan{Promote: true}).Next("v1.2.3"); err == nil {
+		t.Fatalf("expected error promoting a final release")
+	}
+	if _, err := (Plan{PreRelease: "beta"}).Next("v1.3.0-rc.1"); err == nil {
+		t.Fatalf("expected error going back from rc to beta")
+	}
+	if _, err := (Plan{PreRelease: "gamma"}).Next("v1.3.0"); err == nil {
+		t.Fatalf("expected error for unsupported channel")
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tags := []string{"v1.9.0", "v1.10.0", "v1.11.0-rc.1", "nightly", "api/v2.0.0", "v1.10"}
+	if got, ok := Latest(tags, "v", false); !ok || got != "v1.10.0" {
+		t.Fatalf("expected v1.10.0, got %q", got)
+	}
+	if got, _ := Latest(tags, "v", true); got != "v1.11.0-rc.1" {
+		t.Fatalf("expected v1.11.0-rc.1, got %q", got)
+	}
+	if got, _ := Latest(tags, "api/v", false); got != "api/v2.0.0" {
+		t.Fatalf("expected api/v2.0.0, got %q", got)
+	}
+	if _, ok := Latest([]string{"nightly"}, "v", true); ok {
+		t.Fatalf("expected no release tag")
+	}
+}